@@ -0,0 +1,220 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"sort"
+	"strings"
+)
+
+type AlgoMode int
+
+const (
+	AlgoModeStream        AlgoMode = iota // algo().stream(), returns results
+	AlgoModeStats                         // algo().stats(), returns statistics
+	AlgoModeWriteProperty                 // algo().write({db:{property}}) by exec task
+	AlgoModeWriteFile                     // algo().write({file:{filename}}) by exec task
+)
+
+// AlgoOptions options of RunAlgo, the zero value streams results under alias "results"
+type AlgoOptions struct {
+	Mode           AlgoMode
+	Alias          string // alias of stream or stats, default is "results" or "stats"
+	Property       string // property to write back, required by AlgoModeWriteProperty
+	FileName       string // file to write, required by AlgoModeWriteFile
+	NoWait         bool   // don't wait for the task of write modes, check AlgoResult.Task later
+	SkipValidation bool   // don't check the algo and params against ShowAlgo
+	LoadProperties bool   // load the properties referenced by params to engine before running, check EnsureAlgoPropertiesLoaded
+	Config         *configuration.RequestConfig
+}
+
+// AlgoResult result of RunAlgo, Data is set for stream and stats modes, Task is set for write modes
+type AlgoResult struct {
+	Algo     *structs.Algo // installed algo, nil if SkipValidation
+	Mode     AlgoMode
+	Uql      string
+	Response *http.UQLResponse
+	Data     map[string]interface{} // alias => []*structs.Node, []*structs.Edge, []*structs.Path, *structs.Table or *structs.Attr
+	Task     *Task
+}
+
+// Get returns the typed data of alias
+func (r *AlgoResult) Get(alias string) interface{} {
+	return r.Data[alias]
+}
+
+// RunAlgo checks params against the installed algo, builds the algo uql in the mode of opts and runs it.
+// Write modes are sent as exec task, and waited by ctx unless opts.NoWait is set
+// Usage: RunAlgo(ctx, "louvain", map[string]interface{}{"phase1_loop_num": 5}, &AlgoOptions{Mode: AlgoModeWriteProperty, Property: "community"})
+func (api *UltipaAPI) RunAlgo(ctx context.Context, name string, params map[string]interface{}, opts *AlgoOptions) (*AlgoResult, error) {
+	if opts == nil {
+		opts = &AlgoOptions{}
+	}
+	config := &configuration.RequestConfig{}
+	if opts.Config != nil {
+		copied := *opts.Config
+		config = &copied
+	}
+	config.Context = ctx
+
+	result := &AlgoResult{Mode: opts.Mode}
+
+	if !opts.SkipValidation {
+		algos, err := api.ShowAlgo(config)
+		if err != nil {
+			return nil, err
+		}
+		result.Algo, err = ValidateAlgoParams(algos, name, params)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	uql, err := BuildAlgoUql(name, params, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Uql = uql
+
+	if opts.LoadProperties {
+		if _, err = api.EnsureAlgoPropertiesLoaded(ctx, params, config); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Mode == AlgoModeWriteProperty || opts.Mode == AlgoModeWriteFile {
+		result.Task, err = api.TaskManager().Submit(uql, config)
+		if err != nil {
+			return nil, err
+		}
+		if !opts.NoWait {
+			err = result.Task.Wait(ctx)
+		}
+		return result, err
+	}
+
+	result.Response, err = api.UQL(uql, config)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Response.IsSuccess() {
+		return result, errors.New(fmt.Sprintf("failed to run algo %s: %s", name, result.Response.Status.Message))
+	}
+
+	result.Data = map[string]interface{}{}
+	for _, alias := range result.Response.AliasList {
+		result.Data[alias], err = algoResultData(result.Response.Alias(alias))
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// ValidateAlgoParams finds the algo by name, and checks that every param is declared by its Params
+func ValidateAlgoParams(algos []*structs.Algo, name string, params map[string]interface{}) (*structs.Algo, error) {
+	var algo *structs.Algo
+	for _, a := range algos {
+		if a.Name == name {
+			algo = a
+			break
+		}
+	}
+	if algo == nil {
+		return nil, errors.New(fmt.Sprintf("algo %s is not installed", name))
+	}
+
+	var unknown []string
+	for key := range params {
+		if _, ok := algo.Params[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		var known []string
+		for key := range algo.Params {
+			known = append(known, key)
+		}
+		sort.Strings(unknown)
+		sort.Strings(known)
+		return nil, errors.New(fmt.Sprintf("unknown params [%s] of algo %s, valid params are [%s]", strings.Join(unknown, ", "), name, strings.Join(known, ", ")))
+	}
+	return algo, nil
+}
+
+// BuildAlgoUql builds algo(name).params({...}) in the mode of opts, params are formatted by utils.FormatUqlValue
+func BuildAlgoUql(name string, params map[string]interface{}, opts *AlgoOptions) (string, error) {
+	if opts == nil {
+		opts = &AlgoOptions{}
+	}
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	uql := "algo($name).params($params)"
+	uqlParams := map[string]interface{}{
+		"name":   utils.UqlName(name),
+		"params": params,
+	}
+
+	switch opts.Mode {
+	case AlgoModeStream, AlgoModeStats:
+		method := "stream"
+		alias := opts.Alias
+		if alias == "" {
+			alias = "results"
+		}
+		if opts.Mode == AlgoModeStats {
+			method = "stats"
+			if opts.Alias == "" {
+				alias = "stats"
+			}
+		}
+		uql += ".$method() as $alias return $alias"
+		uqlParams["method"] = utils.UqlRaw(method)
+		uqlParams["alias"] = utils.UqlName(alias)
+	case AlgoModeWriteProperty:
+		if opts.Property == "" {
+			return "", errors.New("property is required to write algo results back")
+		}
+		uql += ".write($target)"
+		uqlParams["target"] = map[string]interface{}{"db": map[string]interface{}{"property": opts.Property}}
+	case AlgoModeWriteFile:
+		if opts.FileName == "" {
+			return "", errors.New("file name is required to write algo results to file")
+		}
+		uql += ".write($target)"
+		uqlParams["target"] = map[string]interface{}{"file": map[string]interface{}{"filename": opts.FileName}}
+	default:
+		return "", errors.New(fmt.Sprintf("unknown algo mode %d", opts.Mode))
+	}
+
+	boundUql, err := utils.BindUqlParams(uql, uqlParams)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("algo %s: %s", name, err.Error()))
+	}
+	return boundUql, nil
+}
+
+func algoResultData(item *http.DataItem) (interface{}, error) {
+	switch item.Type {
+	case ultipa.ResultType_RESULT_TYPE_NODE:
+		nodes, _, err := item.AsNodes()
+		return nodes, err
+	case ultipa.ResultType_RESULT_TYPE_EDGE:
+		edges, _, err := item.AsEdges()
+		return edges, err
+	case ultipa.ResultType_RESULT_TYPE_PATH:
+		return item.AsPaths()
+	case ultipa.ResultType_RESULT_TYPE_TABLE:
+		return item.AsTable()
+	case ultipa.ResultType_RESULT_TYPE_ATTR:
+		return item.AsAttr()
+	}
+	return item.Data, nil
+}
@@ -1,23 +1,33 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
 	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
 	"github.com/ultipa/ultipa-go-sdk/sdk/connection"
 	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
 	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
 	"github.com/ultipa/ultipa-go-sdk/sdk/utils/logger"
 	"strconv"
+	"sync"
 	"time"
 )
 
 // UQL, Insert, Export, Download ... API methods
 
 type UltipaAPI struct {
-	Pool   *connection.ConnectionPool
-	Config *configuration.UltipaConfig
-	Logger *logger.Logger
+	Pool         *connection.ConnectionPool
+	Config       *configuration.UltipaConfig
+	Logger       *logger.Logger
+	QueryCache   *UqlCache        // cache of read-only UQL responses, nil if disabled, see UltipaConfig.QueryCacheSize
+	SlowQueryLog *SlowQueryLog    // records slow UQL, nil if disabled, see UltipaConfig.SlowQueryThreshold
+	Permissions  *PermissionCache // cache of CanI results, see RequestConfig.Preflight
+
+	serverVersions     map[string]*structs.ServerVersion // host : version, see DetectServerVersions
+	detectedHosts      map[string]bool                   // hosts detected or failed to detect, see detectNewServerVersions
+	serverVersionsLock sync.RWMutex
 }
 
 type ClientType int
@@ -30,9 +40,26 @@ const (
 func NewUltipaAPI(pool *connection.ConnectionPool) *UltipaAPI {
 
 	api := &UltipaAPI{
-		Pool:   pool,
-		Config: pool.Config,
-		Logger: logger.NewLogger(pool.Config.Debug),
+		Pool:        pool,
+		Config:      pool.Config,
+		Logger:      logger.NewLogger(pool.Config.Debug),
+		Permissions: NewPermissionCache(DefaultPermissionCacheTTL),
+	}
+
+	if pool.Config.QueryCacheSize > 0 {
+		api.QueryCache = NewUqlCache(pool.Config.QueryCacheSize, time.Duration(pool.Config.QueryCacheTTL)*time.Second)
+	}
+
+	if pool.Config.SlowQueryThreshold > 0 {
+		api.SlowQueryLog = NewSlowQueryLog(time.Duration(pool.Config.SlowQueryThreshold)*time.Millisecond, SlowQueryLoggerSink{})
+		if pool.Config.SlowQueryLogFile != "" {
+			sink, err := NewSlowQueryFileSink(pool.Config.SlowQueryLogFile, int64(pool.Config.SlowQueryLogMaxSize)*1024*1024, pool.Config.SlowQueryLogBackups)
+			if err != nil {
+				logger.PrintError(err.Error() + ", slow queries are written into the sdk log instead")
+			} else {
+				api.SlowQueryLog.Sink = sink
+			}
+		}
 	}
 
 	return api
@@ -46,7 +73,7 @@ func (api *UltipaAPI) GetConn(config *configuration.RequestConfig) (*connection.
 
 	if config != nil {
 		conf = api.Pool.Config.MergeRequestConfig(config)
-		UqlItem := utils.NewUql(config.Uql)
+		uqlClass := utils.ClassifyUql(config.Uql)
 
 		// Check if User set Host Address
 		if config.Host != "" {
@@ -57,18 +84,17 @@ func (api *UltipaAPI) GetConn(config *configuration.RequestConfig) (*connection.
 
 			// if is raft mode, check if contains CUD ops or exec task
 		} else if api.Pool.IsRaft {
-			if UqlItem.IsGlobal() || config.UseControl {
+			if uqlClass.Global || config.UseControl {
 				conn, err = api.Pool.GetGlobalMasterConn(conf)
-				if UqlItem.IsGlobal() {
+				if uqlClass.Global {
 					conf.CurrentGraph = "global"
 				}
-			} else if UqlItem.HasWrite() || config.UseMaster || conf.Consistency {
-				ok, graph := UqlItem.ParseGraph()
-				if ok && graph != "" {
-					conf.CurrentGraph = graph
+			} else if uqlClass.Write || config.UseMaster || conf.Consistency {
+				if uqlClass.Graph != "" {
+					conf.CurrentGraph = uqlClass.Graph
 				}
 				conn, err = api.Pool.GetMasterConn(conf)
-			} else if UqlItem.HasExecTask() {
+			} else if uqlClass.ExecTask {
 				conn, err = api.Pool.GetAnalyticsConn(conf)
 			}
 		}
@@ -89,16 +115,22 @@ func (api *UltipaAPI) GetConn(config *configuration.RequestConfig) (*connection.
 }
 
 func (api *UltipaAPI) GetClient(config *configuration.RequestConfig) (ultipa.UltipaRpcsClient, *configuration.UltipaConfig, error) {
+	client, _, conf, err := api.getClientAndConn(config)
+	return client, conf, err
+}
+
+// getClientAndConn is GetClient, returns the hit connection as well
+func (api *UltipaAPI) getClientAndConn(config *configuration.RequestConfig) (ultipa.UltipaRpcsClient, *connection.Connection, *configuration.UltipaConfig, error) {
 
 	conn, conf, err := api.GetConn(config)
 
 	if err != nil {
-		return nil, conf, err
+		return nil, nil, conf, err
 	}
 
 	client := conn.GetClient()
 	api.Logger.Log(fmt.Sprintf("fetch client,  hit host:[%s], role [%v], graph=[%s]", conn.Host, conn.Role, conf.CurrentGraph))
-	return client, conf, nil
+	return client, conn, conf, nil
 }
 
 func (api *UltipaAPI) GetControlClient(config *configuration.RequestConfig) (ultipa.UltipaControlsClient, error) {
@@ -108,6 +140,12 @@ func (api *UltipaAPI) GetControlClient(config *configuration.RequestConfig) (ult
 }
 
 func (api *UltipaAPI) GetControlClientAndConfig(config *configuration.RequestConfig) (ultipa.UltipaControlsClient, *configuration.UltipaConfig, error) {
+	client, _, conf, err := api.getControlClientAndConn(config)
+	return client, conf, err
+}
+
+// getControlClientAndConn is GetControlClientAndConfig, returns the hit connection as well
+func (api *UltipaAPI) getControlClientAndConn(config *configuration.RequestConfig) (ultipa.UltipaControlsClient, *connection.Connection, *configuration.UltipaConfig, error) {
 
 	if config == nil {
 		config = &configuration.RequestConfig{}
@@ -118,11 +156,11 @@ func (api *UltipaAPI) GetControlClientAndConfig(config *configuration.RequestCon
 	conn, conf, err := api.GetConn(config)
 
 	if err != nil {
-		return nil, conf, err
+		return nil, nil, conf, err
 	}
 	client := conn.GetControlClient()
 	api.Logger.Log(fmt.Sprintf("fetch control client, hit host:[%s], role [%v], graph=[%s]", conn.Host, conn.Role, conf.CurrentGraph))
-	return client, conf, nil
+	return client, conn, conf, nil
 }
 
 // UQL send a uql string to ultipa graph, and return a http UQL Response
@@ -130,18 +168,35 @@ func (api *UltipaAPI) GetControlClientAndConfig(config *configuration.RequestCon
 // Check DataItem to learn more about UQL Response
 func (api *UltipaAPI) UQL(uql string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
 
-	resp, conf, err := api.doExecuteUql(uql, config)
+	// the permission is checked before the query cache, so a denied user never reads cached results
+	if err := api.preflight(OperationUQL, uql, config); err != nil {
+		return nil, err
+	}
+
+	cache, graph, cacheVersion := api.queryCacheFor(uql, config)
+	if cache != nil {
+		if cached, ok := cache.Get(graph, uql); ok {
+			return cached, nil
+		}
+	}
+
+	start := time.Now()
+	resp, conn, conf, cancel, err := api.doExecuteUql(uql, config)
 	if err != nil {
+		api.logSlowQuery(uql, config, conf, conn, start, nil, err)
 		return nil, err
 	}
+	defer cancel()
 
 	uqlResp, err := http.NewUQLResponse(resp)
 
 	if err != nil {
+		api.logSlowQuery(uql, config, conf, conn, start, nil, err)
 		return nil, err
 	}
 
 	if config != nil && config.Host != "" {
+		api.logSlowQuery(uql, config, conf, conn, start, uqlResp, nil)
 		return uqlResp, err
 	}
 
@@ -153,29 +208,91 @@ func (api *UltipaAPI) UQL(uql string, config *configuration.RequestConfig) (*htt
 		return api.UQL(uql, config)
 	}
 
+	if cache != nil && uqlResp.IsSuccess() {
+		cache.setSince(cacheVersion, graph, uql, uqlResp)
+	} else {
+		// writes may commit after the invalidation in doExecuteUql, invalidate again when finished
+		api.invalidateQueryCache(utils.NewUql(uql), conf.CurrentGraph)
+	}
+	api.logSlowQuery(uql, config, conf, conn, start, uqlResp, nil)
+
 	return uqlResp, nil
 }
 
+// UQLWithParams bind params to the $placeholders of uql, then send it by UQL
+// Usage: UQLWithParams(`find().nodes({name == $name}) as n return n`, map[string]interface{}{"name": "Alice"}, nil)
+// Check utils.BindUqlParams and utils.FormatUqlValue to learn how values are escaped and formatted
+func (api *UltipaAPI) UQLWithParams(uql string, params map[string]interface{}, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	boundUql, err := utils.BindUqlParams(uql, params)
+	if err != nil {
+		return nil, err
+	}
+	return api.UQL(boundUql, config)
+}
+
 func (api *UltipaAPI) UQLStream(uql string, config *configuration.RequestConfig) (*http.UQLResponseStream, error) {
-	resp, conf, err := api.doExecuteUql(uql, config)
+	if err := api.preflight(OperationUQL, uql, config); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	resp, conn, conf, cancel, err := api.doExecuteUql(uql, config)
 	if err != nil {
+		api.logSlowQuery(uql, config, conf, conn, start, nil, err)
 		return nil, err
 	}
 	uqlResp, err := http.NewUQLResponseStream(resp)
+	uqlResp.Cancel = cancel
 	if config != nil && config.Host != "" {
+		uqlResp.OnClose(api.logSlowStream(uql, config, conf, conn, start))
 		return uqlResp, err
 	}
 	if uqlResp.NeedRedirect() {
+		cancel()
 		err = api.Pool.RefreshClusterInfo(conf.CurrentGraph)
 		if err != nil {
 			return nil, err
 		}
 		return api.UQLStream(uql, config)
 	}
+	uqlResp.OnClose(api.logSlowStream(uql, config, conf, conn, start))
 	return uqlResp, nil
 }
 
-func (api *UltipaAPI) doExecuteUql(uql string, config *configuration.RequestConfig) (ultipa.UltipaRpcs_UqlClient, *configuration.UltipaConfig, error) {
+// Query send a uql string to ultipa graph, and return a Rows cursor over the first alias, call Rows.Select to iterate another alias.
+// Rows are decoded one by one from the reply stream, so large results are not merged in memory, check http.Rows to learn more
+// Usage: rows, err := Query("find().nodes() as n return n{*}", nil); defer rows.Close(); for rows.Next() { rows.Scan(&node) }
+func (api *UltipaAPI) Query(uql string, config *configuration.RequestConfig) (*http.Rows, error) {
+	if err := api.preflight(OperationUQL, uql, config); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	resp, conn, conf, cancel, err := api.doExecuteUql(uql, config)
+	if err != nil {
+		api.logSlowQuery(uql, config, conf, conn, start, nil, err)
+		return nil, err
+	}
+	rows, err := http.NewRows(resp, cancel)
+	if err != nil {
+		api.logSlowQuery(uql, config, conf, conn, start, nil, err)
+		return nil, err
+	}
+	if config != nil && config.Host != "" {
+		rows.OnClose(api.logSlowStream(uql, config, conf, conn, start))
+		return rows, nil
+	}
+	if rows.NeedRedirect() {
+		err = api.Pool.RefreshClusterInfo(conf.CurrentGraph)
+		if err != nil {
+			return nil, err
+		}
+		return api.Query(uql, config)
+	}
+	rows.OnClose(api.logSlowStream(uql, config, conf, conn, start))
+	return rows, nil
+}
+
+// doExecuteUql sends uql and returns the reply stream and the hit connection, cancel must be called after the stream is consumed
+func (api *UltipaAPI) doExecuteUql(uql string, config *configuration.RequestConfig) (ultipa.UltipaRpcs_UqlClient, *connection.Connection, *configuration.UltipaConfig, context.CancelFunc, error) {
 	var err error
 
 	if config == nil {
@@ -187,22 +304,27 @@ func (api *UltipaAPI) doExecuteUql(uql string, config *configuration.RequestConf
 	isExtra := uqlItem.IsExtra()
 	var client ultipa.UltipaRpcsClient
 	var uqlExClient ultipa.UltipaControlsClient
+	var conn *connection.Connection
 	var conf *configuration.UltipaConfig
 	if isExtra {
-		uqlExClient, conf, err = api.GetControlClientAndConfig(config)
+		uqlExClient, conn, conf, err = api.getControlClientAndConn(config)
 	} else {
-		client, conf, err = api.GetClient(config)
+		client, conn, conf, err = api.getClientAndConn(config)
 	}
 
 	if err != nil {
-		return nil, conf, err
+		return nil, conn, conf, nil, err
 	}
 	//CurrentGraph of conf may be changed by uql
 	config.GraphName = conf.CurrentGraph
+	api.invalidateQueryCache(uqlItem, conf.CurrentGraph)
+	api.invalidatePermissionCache(uqlItem)
 	ctx, cancel, err := api.Pool.NewContext(config)
 	if err != nil {
-		defer cancel()
-		return nil, conf, err
+		return nil, conn, conf, nil, err
+	}
+	if config.KillOnCancel && config.Context != nil {
+		cancel = api.killOnCancel(config.Context, uql, conf.CurrentGraph, conn.Host, cancel)
 	}
 	uqlRequest := api.buildUqlRequest(uql, config, conf)
 	var resp ultipa.UltipaRpcs_UqlClient
@@ -217,7 +339,8 @@ func (api *UltipaAPI) doExecuteUql(uql string, config *configuration.RequestConf
 		err = api.Pool.RefreshClusterInfo(conf.CurrentGraph)
 
 		if err != nil {
-			return nil, conf, err
+			cancel()
+			return nil, conn, conf, nil, err
 		}
 
 		if isExtra {
@@ -227,10 +350,11 @@ func (api *UltipaAPI) doExecuteUql(uql string, config *configuration.RequestConf
 		}
 
 		if err != nil {
-			return nil, conf, err
+			cancel()
+			return nil, conn, conf, nil, err
 		}
 	}
-	return resp, conf, nil
+	return resp, conn, conf, cancel, nil
 }
 
 // buildUqlRequest build uqlRequest according to requestConfig and configuration
@@ -306,12 +430,26 @@ func (api *UltipaAPI) SetCurrentGraph(graphName string) error {
 }
 
 func (api *UltipaAPI) Close() error {
+	api.closeSlowQueryLog()
 	return api.Pool.Close()
 }
 
 func (api *UltipaAPI) SafelyClose() error {
+	if api != nil {
+		api.closeSlowQueryLog()
+	}
 	if api != nil && api.Pool != nil {
 		return api.Pool.Close()
 	}
 	return nil
 }
+
+// closeSlowQueryLog closes the slow query log file opened by NewUltipaAPI
+func (api *UltipaAPI) closeSlowQueryLog() {
+	if api.SlowQueryLog == nil {
+		return
+	}
+	if err := api.SlowQueryLog.Close(); err != nil {
+		logger.PrintError(err.Error())
+	}
+}
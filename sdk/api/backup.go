@@ -9,6 +9,9 @@ import (
 
 //Backup backup ultipa database data to directory backupToDirectory on server
 func (api *UltipaAPI) Backup(backupToDirectory string, req *configuration.RequestConfig) (*http.UQLResponse, error) {
+	if err := api.requireFeature(FeatureBackup); err != nil {
+		return nil, err
+	}
 	requestConfig := req
 	if req != nil {
 		requestConfig = &configuration.RequestConfig{
@@ -0,0 +1,226 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/connection"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils/logger"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Feature an rpc or uql feature not supported by every server version
+type Feature string
+
+const (
+	FeatureExta               Feature = "exta"                // InstallExta, UninstallExta and show().exta()
+	FeatureUploader           Feature = "uploader"            // Uploader and CreateGraphByUploader
+	FeatureUserSetting        Feature = "user setting"        // UserSetting
+	FeatureBackup             Feature = "backup"              // Backup
+	FeatureCompactGraph       Feature = "compact().graph()"   // compact().graph()
+	FeaturePropertyPrivileges Feature = "property privileges" // property privileges of users and policies
+)
+
+// FeatureMinVersions the minimum server version of each feature
+var FeatureMinVersions = map[Feature]string{
+	FeatureExta:               "4.2.0",
+	FeatureUploader:           "4.3.0",
+	FeatureUserSetting:        "4.3.0",
+	FeatureBackup:             "4.3.0",
+	FeatureCompactGraph:       "4.3.0",
+	FeaturePropertyPrivileges: "4.3.0",
+}
+
+// Capabilities the features supported by a server version
+type Capabilities struct {
+	Version  *structs.ServerVersion // nil if the version is unknown, then every feature is assumed to be supported
+	Features map[Feature]bool
+}
+
+func NewCapabilities(version *structs.ServerVersion) *Capabilities {
+	capabilities := &Capabilities{
+		Version:  version,
+		Features: map[Feature]bool{},
+	}
+	for feature, minVersion := range FeatureMinVersions {
+		min, err := structs.ParseServerVersion(minVersion)
+		capabilities.Features[feature] = version == nil || err != nil || version.AtLeast(min)
+	}
+	return capabilities
+}
+
+// Supports returns whether the feature is supported, a feature not in FeatureMinVersions is supported
+func (c *Capabilities) Supports(feature Feature) bool {
+	supported, ok := c.Features[feature]
+	return !ok || supported
+}
+
+// Require returns a utils.UnsupportedFeatureError if the feature is not supported
+func (c *Capabilities) Require(feature Feature) error {
+	if c.Supports(feature) {
+		return nil
+	}
+	return utils.NewUnsupportedFeatureError(string(feature), FeatureMinVersions[feature], c.Version.String())
+}
+
+// DetectServerVersions gets the version of each host by stats() and caches it, it is called when the client is created,
+// hosts added later, e.g. by RefreshClusterInfo, are detected when Capabilities is called.
+// Hosts failed to detect keep their cached versions, an error is returned if no version is detected
+func (api *UltipaAPI) DetectServerVersions() error {
+	hosts := api.serverHosts()
+	api.markServerVersionsDetected(hosts)
+	return api.detectServerVersions(hosts)
+}
+
+// detectNewServerVersions detects the hosts which are never detected, a failed host is not retried until DetectServerVersions is called
+func (api *UltipaAPI) detectNewServerVersions() {
+	hosts := api.markServerVersionsDetected(api.serverHosts())
+	if len(hosts) == 0 {
+		return
+	}
+	if err := api.detectServerVersions(hosts); err != nil {
+		logger.PrintWarn(err.Error())
+	}
+}
+
+// serverHosts returns the sorted hosts of the pool, hosts are snapshotted since they may be added by RefreshClusterInfo
+func (api *UltipaAPI) serverHosts() []string {
+	var hosts []string
+	for host := range api.Pool.ConnectionsSnapshot() {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// markServerVersionsDetected marks hosts as detected, and returns the hosts which were not marked
+func (api *UltipaAPI) markServerVersionsDetected(hosts []string) []string {
+	api.serverVersionsLock.Lock()
+	defer api.serverVersionsLock.Unlock()
+	if api.detectedHosts == nil {
+		api.detectedHosts = map[string]bool{}
+	}
+	var marked []string
+	for _, host := range hosts {
+		if !api.detectedHosts[host] {
+			api.detectedHosts[host] = true
+			marked = append(marked, host)
+		}
+	}
+	return marked
+}
+
+func (api *UltipaAPI) detectServerVersions(hosts []string) error {
+	var failures []string
+	detected := 0
+	for _, host := range hosts {
+		conn := api.Pool.GetConnection(host)
+		if conn == nil || conn.Active == ultipa.ServerStatus_DEAD {
+			continue
+		}
+		version, err := api.detectServerVersion(conn)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", host, err))
+			continue
+		}
+		api.setServerVersion(host, version)
+		detected++
+	}
+	if detected == 0 && len(failures) > 0 {
+		return errors.New("failed to detect server version, " + strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// DefaultServerVersionTimeout is how long to wait for stats() of a host when detecting its version, so a hung host does not block the client
+var DefaultServerVersionTimeout = 5 * time.Second
+
+func (api *UltipaAPI) detectServerVersion(conn *connection.Connection) (*structs.ServerVersion, error) {
+	timeoutCtx, cancelTimeout := context.WithTimeout(context.Background(), DefaultServerVersionTimeout)
+	defer cancelTimeout()
+	config := &configuration.RequestConfig{Context: timeoutCtx}
+	ctx, cancel, err := api.Pool.NewContext(config)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	stream, err := conn.GetControlClient().UqlEx(ctx, api.buildUqlRequest("stats()", config, api.Config))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.NewUQLResponse(stream)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := serverVersionOf(resp)
+	if err != nil {
+		return nil, err
+	}
+	return structs.ParseServerVersion(raw)
+}
+
+// serverVersionOf returns the version of the _statistic table of stats(), empty if there is no version
+func serverVersionOf(resp *http.UQLResponse) (string, error) {
+	if !resp.IsSuccess() {
+		return "", errors.New(resp.Status.Message)
+	}
+	stat, err := resp.Alias(http.RESP_STATISTIC_KEY).AsTable()
+	if err != nil {
+		return "", err
+	}
+	if stat == nil {
+		return "", errors.New("no " + http.RESP_STATISTIC_KEY + " in the response of stats()")
+	}
+	rows := stat.ToKV()
+	if len(rows) > 0 {
+		if version, ok := rows[0].Get("version").(string); ok {
+			return version, nil
+		}
+	}
+	return "", nil
+}
+
+func (api *UltipaAPI) setServerVersion(host string, version *structs.ServerVersion) {
+	api.serverVersionsLock.Lock()
+	defer api.serverVersionsLock.Unlock()
+	if api.serverVersions == nil {
+		api.serverVersions = map[string]*structs.ServerVersion{}
+	}
+	api.serverVersions[host] = version
+}
+
+// ServerVersion returns the cached version of host, nil if it is not detected
+func (api *UltipaAPI) ServerVersion(host string) *structs.ServerVersion {
+	api.serverVersionsLock.RLock()
+	defer api.serverVersionsLock.RUnlock()
+	return api.serverVersions[host]
+}
+
+// Capabilities returns the features supported by all the hosts, which are the features of the oldest version detected,
+// hosts never detected are detected first
+func (api *UltipaAPI) Capabilities() *Capabilities {
+	api.detectNewServerVersions()
+
+	api.serverVersionsLock.RLock()
+	defer api.serverVersionsLock.RUnlock()
+	var oldest *structs.ServerVersion
+	for _, version := range api.serverVersions {
+		if oldest == nil || version.Compare(oldest) < 0 {
+			oldest = version
+		}
+	}
+	return NewCapabilities(oldest)
+}
+
+// requireFeature fails early with a utils.UnsupportedFeatureError if the server is too old for the feature
+func (api *UltipaAPI) requireFeature(feature Feature) error {
+	return api.Capabilities().Require(feature)
+}
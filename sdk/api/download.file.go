@@ -9,6 +9,10 @@ import (
 func (api *UltipaAPI) DownloadFileV2(fileName string, taskId string, config *configuration.RequestConfig, receive func(data []byte) error) error {
 	var err error
 
+	if err = api.preflight(OperationDownloadFile, "", config); err != nil {
+		return err
+	}
+
 	client, err := api.GetControlClient(config)
 
 	if err != nil {
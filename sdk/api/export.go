@@ -12,6 +12,10 @@ import (
 func (api *UltipaAPI) ExportAsNodesEdges(schema *structs.Schema, limit int, config *configuration.RequestConfig, cb func(nodes []*structs.Node, edges []*structs.Edge) error) error {
 	var err error
 
+	if err = api.preflight(OperationExport, "", config); err != nil {
+		return err
+	}
+
 	client, err := api.GetControlClient(config)
 
 	if err != nil {
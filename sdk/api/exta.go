@@ -8,40 +8,64 @@
 package api
 
 import (
-	"bufio"
-	"github.com/codingsince1985/checksum"
+	"context"
+	"errors"
+	"fmt"
 	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
 	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
-	"io"
-	"os"
-	"path"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"io/fs"
+	"strings"
 )
 
-func (api *UltipaAPI) InstallExta(extaFilePath string, extaInfoFilePath string, req *configuration.RequestConfig) (*ultipa.InstallExtaReply, error) {
-
-	chunkSize := 1024 * 1024 * 1 // 2MB
+func (api *UltipaAPI) ShowExta(req *configuration.RequestConfig) ([]*structs.Exta, error) {
 
-	// check file status
+	if err := api.requireFeature(FeatureExta); err != nil {
+		return nil, err
+	}
 
-	extaFile, err := os.OpenFile(extaFilePath, os.O_RDONLY, 0644)
+	resp, err := api.UQL("show().exta()", req)
 
 	if err != nil {
 		return nil, err
 	}
 
-	extaFileReader := bufio.NewReader(extaFile)
+	extas, err := resp.Get(0).AsExtas()
 
-	extaFileMD5, _ := checksum.MD5sum(extaFilePath)
+	if err != nil {
+		return nil, err
+	}
 
-	extaInfoFile, err := os.OpenFile(extaInfoFilePath, os.O_RDONLY, 0644)
+	return extas, nil
+}
 
+// InstallExta installs the exta package of the .so file and the .yml file, the files are not checked against the .yml, check InstallExtaFS for that,
+// a failed status of server is returned in the reply
+func (api *UltipaAPI) InstallExta(extaFilePath string, extaInfoFilePath string, req *configuration.RequestConfig) (*ultipa.InstallExtaReply, error) {
+	so, err := localPackageFile(extaFilePath)
 	if err != nil {
 		return nil, err
 	}
+	yml, err := localPackageFile(extaInfoFilePath)
+	if err != nil {
+		return nil, err
+	}
+	status, err := api.sendPackage(requestContext(req), "exta", "", so, yml, &InstallOptions{Config: req})
+	if err != nil {
+		return nil, err
+	}
+	return &ultipa.InstallExtaReply{Status: status}, nil
+}
 
-	extaInfoFileReader := bufio.NewReader(extaInfoFile)
+func (api *UltipaAPI) UninstallExta(extaName string, req *configuration.RequestConfig) (*ultipa.UninstallExtaReply, error) {
 
-	extaInfoFileMD5, _ := checksum.MD5sum(extaInfoFilePath)
+	if err := api.requireFeature(FeatureExta); err != nil {
+		return nil, err
+	}
+
+	if err := api.preflight(OperationUninstallExta, "", req); err != nil {
+		return nil, err
+	}
 
 	client, err := api.GetControlClient(req)
 
@@ -55,94 +79,114 @@ func (api *UltipaAPI) InstallExta(extaFilePath string, extaInfoFilePath string,
 	}
 	defer cancel()
 
-	streamClient, err := client.InstallExta(ctx)
+	reply, err := client.UninstallExta(ctx, &ultipa.UninstallExtaRequest{
+		ExtaName: extaName,
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	// send exta so file
-
-	for {
-
-		chunk := make([]byte, chunkSize)
-		n, err := extaFileReader.Read(chunk)
-
-		if err == io.EOF {
-			break
-		}
-
-		if err != nil {
-			return nil, err
-		}
+	return reply, nil
+}
 
-		err = streamClient.Send(&ultipa.InstallExtaRequest{
-			FileName: path.Base(extaFile.Name()),
-			Md5:      extaFileMD5,
-			Chunk:    chunk[:n],
-		})
+// PackageFiles the .so and .yml files of a package in FS
+type PackageFiles struct {
+	FS      fs.FS
+	SoFile  string
+	YmlFile string
+}
 
-		if err != nil {
-			return nil, err
-		}
+func (p *PackageFiles) packageFiles() (*packageFile, *packageFile, error) {
+	so, err := fsPackageFile(p.FS, p.SoFile)
+	if err != nil {
+		return nil, nil, err
 	}
+	yml, err := fsPackageFile(p.FS, p.YmlFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return so, yml, nil
+}
 
-	// send exta info file
-	for {
-		chunk := make([]byte, chunkSize)
-
-		n, err := extaInfoFileReader.Read(chunk)
+// UpgradeExtaResult result of UpgradeExta
+type UpgradeExtaResult struct {
+	Previous   *structs.Exta // the exta installed before, nil if it was not installed
+	Installed  *PackageInfo  // the package installed, which is the previous package if RolledBack
+	RolledBack bool
+}
 
-		if err == io.EOF {
-			break
-		}
+// UpgradeExta uninstalls the exta of pkg and installs pkg, if the install fails the previous package is installed again.
+// Both packages are checked before uninstalling, previous must be the package of the installed exta, it is not needed if the exta is not installed
+func (api *UltipaAPI) UpgradeExta(ctx context.Context, pkg *PackageFiles, previous *PackageFiles, opts *InstallOptions) (*UpgradeExtaResult, error) {
+	if opts == nil {
+		opts = &InstallOptions{}
+	}
+	so, yml, err := pkg.packageFiles()
+	if err != nil {
+		return nil, err
+	}
+	info, err := checkPackage(so, yml)
+	if err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return nil, err
+	extas, err := api.ShowExta(opts.Config)
+	if err != nil {
+		return nil, err
+	}
+	result := &UpgradeExtaResult{}
+	for _, exta := range extas {
+		if strings.EqualFold(exta.Name, info.Name) {
+			result.Previous = exta
 		}
-
-		err = streamClient.Send(&ultipa.InstallExtaRequest{
-			FileName: path.Base(extaInfoFile.Name()),
-			Md5:      extaInfoFileMD5,
-			Chunk:    chunk[:n],
-		})
-
-		if err != nil {
+	}
+	if result.Previous == nil {
+		if result.Installed, err = api.installPackageChecked(ctx, "exta", so, yml, opts); err != nil {
 			return nil, err
 		}
+		return result, nil
 	}
 
-	reply, err := streamClient.CloseAndRecv()
-
+	if previous == nil {
+		return nil, errors.New(fmt.Sprintf("the previous package of exta %s is required to roll back", info.Name))
+	}
+	previousSo, previousYml, err := previous.packageFiles()
 	if err != nil {
 		return nil, err
 	}
-
-	return reply, nil
-
-}
-
-func (api *UltipaAPI) UninstallExta(extaName string, req *configuration.RequestConfig) (*ultipa.UninstallExtaReply, error) {
-
-	client, err := api.GetControlClient(req)
-
+	previousInfo, err := checkPackage(previousSo, previousYml)
 	if err != nil {
 		return nil, err
 	}
+	if !strings.EqualFold(previousInfo.Name, info.Name) {
+		return nil, errors.New(fmt.Sprintf("previous package %s is not exta %s", previousInfo.Name, info.Name))
+	}
+	if result.Previous.Version != "" && previousInfo.Version != "" && result.Previous.Version != previousInfo.Version {
+		return nil, errors.New(fmt.Sprintf("previous package of exta %s is version %s, but version %s is installed", info.Name, previousInfo.Version, result.Previous.Version))
+	}
 
-	ctx, cancel, err := api.Pool.NewContext(req)
+	reply, err := api.UninstallExta(result.Previous.Name, opts.Config)
 	if err != nil {
 		return nil, err
 	}
-	defer cancel()
-
-	reply, err := client.UninstallExta(ctx, &ultipa.UninstallExtaRequest{
-		ExtaName: extaName,
-	})
+	if reply.Status != nil && reply.Status.ErrorCode != ultipa.ErrorCode_SUCCESS {
+		return nil, errors.New(fmt.Sprintf("failed to uninstall exta %s: %s", result.Previous.Name, reply.Status.Msg))
+	}
 
-	if err != nil {
-		return nil, err
+	result.Installed, err = api.installPackageChecked(ctx, "exta", so, yml, opts)
+	if err == nil {
+		return result, nil
 	}
 
-	return reply, nil
+	// roll back without ctx, which may be the reason of the failure
+	rollbackOpts := *opts
+	rollbackOpts.OnProgress = nil
+	var rollbackErr error
+	result.Installed, rollbackErr = api.installPackageChecked(nil, "exta", previousSo, previousYml, &rollbackOpts)
+	if rollbackErr != nil {
+		return result, errors.New(fmt.Sprintf("%v, and failed to roll back to version %s: %v", err, previousInfo.Version, rollbackErr))
+	}
+	result.RolledBack = true
+	return result, errors.New(fmt.Sprintf("%v, rolled back to version %s", err, previousInfo.Version))
 }
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+)
+
+// GraphAdminResult result of AlterGraph, TruncateGraph, MountGraph, UnmountGraph and CompactGraph
+type GraphAdminResult struct {
+	Graph        string // graph targeted by the uql, parsed by UqlItem.ParseGraph
+	Uql          string
+	NodeAffected int
+	EdgeAffected int
+	TotalCost    int
+	EngineCost   int
+}
+
+// TruncateTarget nodes or edges to truncate, an empty Schema or * truncates all the schemas of DBType
+type TruncateTarget struct {
+	DBType ultipa.DBType
+	Schema string
+}
+
+// AlterGraph renames the graph or changes its description, empty fields of graph are not changed
+func (api *UltipaAPI) AlterGraph(graphName string, graph *structs.Graph, config *configuration.RequestConfig) (*GraphAdminResult, error) {
+	values := map[string]interface{}{}
+	if graph.Name != "" && graph.Name != graphName {
+		values["name"] = graph.Name
+	}
+	if graph.Description != "" {
+		values["description"] = graph.Description
+	}
+	if len(values) == 0 {
+		return nil, errors.New("nothing to alter for graph " + graphName)
+	}
+	uql, err := utils.BindUqlParams("alter().graph($graph).set($values)", map[string]interface{}{
+		"graph":  graphName,
+		"values": values,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.runGraphAdmin(uql, graphName, config)
+}
+
+// BuildTruncateGraphUql builds truncate().graph(), target nil truncates the whole graph
+func BuildTruncateGraphUql(graphName string, target *TruncateTarget) (string, error) {
+	uql := "truncate().graph($graph)"
+	params := map[string]interface{}{"graph": graphName}
+	if target != nil {
+		dbType := structs.DBTypeToString(target.DBType)
+		if dbType == "" {
+			return "", errors.New(fmt.Sprintf("unknown db type to truncate: %v", target.DBType))
+		}
+		command := dbType + "s"
+		if target.Schema == "" || target.Schema == "*" {
+			uql += "." + command + "(*)"
+		} else {
+			uql += "." + command + "(@$schema)"
+			params["schema"] = utils.UqlName(target.Schema)
+		}
+	}
+	return utils.BindUqlParams(uql, params)
+}
+
+// TruncateGraph deletes the nodes and edges of the graph, or only the nodes or edges of target
+func (api *UltipaAPI) TruncateGraph(graphName string, target *TruncateTarget, config *configuration.RequestConfig) (*GraphAdminResult, error) {
+	uql, err := BuildTruncateGraphUql(graphName, target)
+	if err != nil {
+		return nil, err
+	}
+	return api.runGraphAdmin(uql, graphName, config)
+}
+
+func (api *UltipaAPI) MountGraph(graphName string, config *configuration.RequestConfig) (*GraphAdminResult, error) {
+	return api.graphCommand("mount", graphName, config)
+}
+
+func (api *UltipaAPI) UnmountGraph(graphName string, config *configuration.RequestConfig) (*GraphAdminResult, error) {
+	return api.graphCommand("unmount", graphName, config)
+}
+
+// CompactGraph compacts the storage of the graph, to free the space of deleted data
+func (api *UltipaAPI) CompactGraph(graphName string, config *configuration.RequestConfig) (*GraphAdminResult, error) {
+	if err := api.requireFeature(FeatureCompactGraph); err != nil {
+		return nil, err
+	}
+	return api.graphCommand("compact", graphName, config)
+}
+
+func (api *UltipaAPI) graphCommand(command string, graphName string, config *configuration.RequestConfig) (*GraphAdminResult, error) {
+	uql, err := utils.BindUqlParams(command+"().graph($graph)", map[string]interface{}{
+		"graph": graphName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.runGraphAdmin(uql, graphName, config)
+}
+
+// runGraphAdmin sends uql to the graph parsed from it, or to graphName, the cached responses of the graph are invalidated
+func (api *UltipaAPI) runGraphAdmin(uql string, graphName string, config *configuration.RequestConfig) (*GraphAdminResult, error) {
+	result := &GraphAdminResult{Graph: graphName, Uql: uql}
+	if ok, graph := utils.NewUql(uql).ParseGraph(); ok {
+		result.Graph = graph
+	}
+	defer api.invalidateGraphCache(result.Graph)
+
+	resp, err := checkUQLResponse(api.UQL(uql, config))
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to run %s on graph %s: %v", utils.NormalizeUql(uql), result.Graph, err))
+	}
+	if resp.Statistic != nil {
+		result.NodeAffected = resp.Statistic.NodeAffected
+		result.EdgeAffected = resp.Statistic.EdgeAffected
+		result.TotalCost = resp.Statistic.TotalCost
+		result.EngineCost = resp.Statistic.EngineCost
+	}
+	return result, nil
+}
+
+// GetGraphStats returns the total nodes and edges of the graph and of each schema
+func (api *UltipaAPI) GetGraphStats(graphName string, config *configuration.RequestConfig) (*structs.GraphStats, error) {
+	graphs, err := api.ListGraph(config)
+	if err != nil {
+		return nil, err
+	}
+	if !graphs.Status.IsSuccess() {
+		return nil, errors.New(graphs.Status.Message)
+	}
+	graph := graphs.Find(graphName)
+	if graph == nil {
+		return nil, errors.New("graph not found: " + graphName)
+	}
+
+	stats := &structs.GraphStats{
+		Name:        graph.Name,
+		Status:      graph.Status,
+		TotalNodes:  graph.TotalNodes,
+		TotalEdges:  graph.TotalEdges,
+		NodeSchemas: map[string]int{},
+		EdgeSchemas: map[string]int{},
+	}
+
+	schemaConfig := &configuration.RequestConfig{}
+	if config != nil {
+		copied := *config
+		schemaConfig = &copied
+	}
+	schemaConfig.GraphName = graphName
+	for _, dbType := range []ultipa.DBType{ultipa.DBType_DBNODE, ultipa.DBType_DBEDGE} {
+		schemas, err := api.ListSchema(dbType, schemaConfig)
+		if err != nil {
+			return nil, err
+		}
+		for _, schema := range schemas {
+			if dbType == ultipa.DBType_DBNODE {
+				stats.NodeSchemas[schema.Name] = schema.Total
+			} else {
+				stats.EdgeSchemas[schema.Name] = schema.Total
+			}
+		}
+	}
+	return stats, nil
+}
@@ -71,7 +71,10 @@ func (api *UltipaAPI) CreateGraphIfNotExit(graph *structs.Graph, config *configu
 
 func (api *UltipaAPI) CreateGraph(graph *structs.Graph, config *configuration.RequestConfig) (*http.UQLResponse, error) {
 
-	resp, err := api.UQL(fmt.Sprintf(`create().graph("%v", "%v")`, graph.Name, graph.Description), config)
+	resp, err := api.UQLWithParams(`create().graph($name, $description)`, map[string]interface{}{
+		"name":        graph.Name,
+		"description": graph.Description,
+	}, config)
 
 	if err != nil {
 		return nil, err
@@ -118,7 +121,9 @@ func (api *UltipaAPI) CreateGraph(graph *structs.Graph, config *configuration.Re
 
 func (api *UltipaAPI) DropGraph(graphName string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
 
-	resp, err := api.UQL(fmt.Sprintf(`drop().graph("%v")`, graphName), config)
+	resp, err := api.UQLWithParams(`drop().graph($name)`, map[string]interface{}{
+		"name": graphName,
+	}, config)
 
 	if err != nil {
 		return nil, err
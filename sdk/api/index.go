@@ -8,11 +8,15 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
 	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
 	"github.com/ultipa/ultipa-go-sdk/sdk/http"
 	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"time"
 )
 
 func (api *UltipaAPI) ListIndex(config *configuration.RequestConfig) ([]*http.ResponseIndex, error) {
@@ -174,3 +178,138 @@ func (api *UltipaAPI) ListNodeFullText(config *configuration.RequestConfig) ([]*
 
 	return indexes, err
 }
+
+var DefaultIndexPollInterval = time.Second
+
+// DefaultIndexNotFoundTimeout is how long WaitIndexReady keeps polling an index not shown yet, e.g. right after it is created
+var DefaultIndexNotFoundTimeout = 10 * time.Second
+
+// dbTypeCommand returns node_kind or edge_kind, e.g. node_index
+func dbTypeCommand(dbType ultipa.DBType, kind string) (string, error) {
+	switch dbType {
+	case ultipa.DBType_DBNODE:
+		return "node_" + kind, nil
+	case ultipa.DBType_DBEDGE:
+		return "edge_" + kind, nil
+	}
+	return "", errors.New(fmt.Sprintf("unknown db type of %s: %v", kind, dbType))
+}
+
+// CreateIndex creates index of @schema.property, the index is built in background, check WaitIndexReady
+func (api *UltipaAPI) CreateIndex(dbType ultipa.DBType, schemaName string, propertyName string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	command, err := dbTypeCommand(dbType, "index")
+	if err != nil {
+		return nil, err
+	}
+	return checkUQLResponse(api.UQLWithParams("create()."+command+"(@$schema.$property)", map[string]interface{}{
+		"schema":   utils.UqlName(schemaName),
+		"property": utils.UqlName(propertyName),
+	}, config))
+}
+
+func (api *UltipaAPI) DropIndex(dbType ultipa.DBType, schemaName string, propertyName string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	command, err := dbTypeCommand(dbType, "index")
+	if err != nil {
+		return nil, err
+	}
+	return checkUQLResponse(api.UQLWithParams("drop()."+command+"(@$schema.$property)", map[string]interface{}{
+		"schema":   utils.UqlName(schemaName),
+		"property": utils.UqlName(propertyName),
+	}, config))
+}
+
+// CreateFullText creates full-text index named name of @schema.property, the index is built in background, check WaitFullTextReady
+func (api *UltipaAPI) CreateFullText(dbType ultipa.DBType, schemaName string, propertyName string, name string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	command, err := dbTypeCommand(dbType, "fulltext")
+	if err != nil {
+		return nil, err
+	}
+	return checkUQLResponse(api.UQLWithParams("create()."+command+"(@$schema.$property, $name)", map[string]interface{}{
+		"schema":   utils.UqlName(schemaName),
+		"property": utils.UqlName(propertyName),
+		"name":     name,
+	}, config))
+}
+
+func (api *UltipaAPI) DropFullText(dbType ultipa.DBType, name string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	command, err := dbTypeCommand(dbType, "fulltext")
+	if err != nil {
+		return nil, err
+	}
+	return checkUQLResponse(api.UQLWithParams("drop()."+command+"($name)", map[string]interface{}{
+		"name": name,
+	}, config))
+}
+
+// WaitIndexReady polls show().index() until the node or edge index of dbType is done or failed, or ctx is done,
+// name is the index name or schema.property, an index not shown is polled for DefaultIndexNotFoundTimeout
+func (api *UltipaAPI) WaitIndexReady(ctx context.Context, dbType ultipa.DBType, name string, config *configuration.RequestConfig) (*structs.Index, error) {
+	config = indexWaitConfig(ctx, config)
+	return waitIndex(config.Context, dbType, name, func() ([]*http.ResponseIndex, error) {
+		return api.ListIndex(config)
+	})
+}
+
+// WaitFullTextReady polls show().fulltext() until the node or edge full-text index of dbType is done or failed, or ctx is done
+func (api *UltipaAPI) WaitFullTextReady(ctx context.Context, dbType ultipa.DBType, name string, config *configuration.RequestConfig) (*structs.Index, error) {
+	config = indexWaitConfig(ctx, config)
+	return waitIndex(config.Context, dbType, name, func() ([]*http.ResponseIndex, error) {
+		return api.ListFullText(config)
+	})
+}
+
+// indexWaitConfig copies config with ctx, so a poll in progress is cancelled with ctx
+func indexWaitConfig(ctx context.Context, config *configuration.RequestConfig) *configuration.RequestConfig {
+	copied := &configuration.RequestConfig{}
+	if config != nil {
+		*copied = *config
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	copied.Context = ctx
+	return copied
+}
+
+func waitIndex(ctx context.Context, dbType ultipa.DBType, name string, list func() ([]*http.ResponseIndex, error)) (*structs.Index, error) {
+	ticker := time.NewTicker(DefaultIndexPollInterval)
+	defer ticker.Stop()
+	start := time.Now()
+
+	for {
+		responseIndexes, err := list()
+		if err != nil {
+			return nil, err
+		}
+		index := FindIndex(responseIndexes, dbType, name)
+		if index == nil && time.Since(start) >= DefaultIndexNotFoundTimeout {
+			return nil, errors.New("index not found: " + name)
+		}
+		if index != nil && index.IsDone() {
+			return index, nil
+		}
+		if index != nil && index.IsFailed() {
+			return index, errors.New(fmt.Sprintf("index %s failed: %s", name, index.Status))
+		}
+		select {
+		case <-ctx.Done():
+			return index, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// FindIndex finds the node or edge index of dbType by its name, or by schema.property
+func FindIndex(responseIndexes []*http.ResponseIndex, dbType ultipa.DBType, name string) *structs.Index {
+	for _, responseIndex := range responseIndexes {
+		if responseIndex == nil || responseIndex.Type != dbType {
+			continue
+		}
+		for _, index := range responseIndex.Indexes {
+			if index.Name == name || index.Schema+"."+index.Properties == name {
+				return index
+			}
+		}
+	}
+	return nil
+}
@@ -16,6 +16,9 @@ import (
 func (api *UltipaAPI) InsertEdgesBatch(table *ultipa.EntityTable, config *configuration.InsertRequestConfig) (*http.InsertResponse, error) {
 
 	config.UseMaster = true
+	if err := api.preflight(OperationInsertEdges, "", config.RequestConfig); err != nil {
+		return nil, err
+	}
 	client, conf, err := api.GetClient(config.RequestConfig)
 
 	if err != nil {
@@ -37,6 +40,7 @@ func (api *UltipaAPI) InsertEdgesBatch(table *ultipa.EntityTable, config *config
 		//Silent:     config.Silent,
 		Silent: true,
 	})
+	api.invalidateGraphCache(conf.CurrentGraph)
 
 	if err != nil {
 		return nil, err
@@ -60,6 +64,9 @@ func (api *UltipaAPI) InsertEdgesBatchBySchema(schema *structs.Schema, rows []*s
 	}
 
 	config.UseMaster = true
+	if err := api.preflight(OperationInsertEdges, "", config.RequestConfig); err != nil {
+		return nil, err
+	}
 	client, conf, err := api.GetClient(config.RequestConfig)
 
 	if err != nil {
@@ -108,6 +115,7 @@ func (api *UltipaAPI) InsertEdgesBatchBySchema(schema *structs.Schema, rows []*s
 		//Silent:     config.Silent,
 		Silent: false,
 	})
+	api.invalidateGraphCache(conf.CurrentGraph)
 
 	if err != nil {
 		return nil, err
@@ -273,6 +281,9 @@ func (api *UltipaAPI) InsertEdgesBatchAuto(edges []*structs.Edge, config *config
 		}
 
 		config.UseMaster = true
+		if err := api.preflight(OperationInsertEdges, "", config.RequestConfig); err != nil {
+			return nil, err
+		}
 		client, conf, err := api.GetClient(config.RequestConfig)
 
 		if err != nil {
@@ -319,6 +330,7 @@ func (api *UltipaAPI) InsertEdgesBatchAuto(edges []*structs.Edge, config *config
 			//Silent:     config.Silent,
 			Silent: true,
 		})
+		api.invalidateGraphCache(conf.CurrentGraph)
 
 		if err != nil {
 			return nil, err
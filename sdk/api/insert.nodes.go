@@ -16,6 +16,9 @@ import (
 func (api *UltipaAPI) InsertNodesBatch(table *ultipa.EntityTable, config *configuration.InsertRequestConfig) (*http.InsertResponse, error) {
 
 	config.UseMaster = true
+	if err := api.preflight(OperationInsertNodes, "", config.RequestConfig); err != nil {
+		return nil, err
+	}
 	client, conf, err := api.GetClient(config.RequestConfig)
 
 	if err != nil {
@@ -36,6 +39,7 @@ func (api *UltipaAPI) InsertNodesBatch(table *ultipa.EntityTable, config *config
 		//Silent:     config.Silent,
 		Silent: true,
 	})
+	api.invalidateGraphCache(conf.CurrentGraph)
 
 	if err != nil {
 		return nil, err
@@ -59,6 +63,9 @@ func (api *UltipaAPI) InsertNodesBatchBySchema(schema *structs.Schema, rows []*s
 	}
 
 	config.UseMaster = true
+	if err := api.preflight(OperationInsertNodes, "", config.RequestConfig); err != nil {
+		return nil, err
+	}
 	client, conf, err := api.GetClient(config.RequestConfig)
 
 	if err != nil {
@@ -106,6 +113,7 @@ func (api *UltipaAPI) InsertNodesBatchBySchema(schema *structs.Schema, rows []*s
 		//Silent:     config.Silent,
 		Silent: true,
 	})
+	api.invalidateGraphCache(conf.CurrentGraph)
 
 	if err != nil {
 		return nil, err
@@ -266,6 +274,9 @@ func (api *UltipaAPI) InsertNodesBatchAuto(nodes []*structs.Node, config *config
 		}
 
 		config.UseMaster = true
+		if err := api.preflight(OperationInsertNodes, "", config.RequestConfig); err != nil {
+			return nil, err
+		}
 		client, conf, err := api.GetClient(config.RequestConfig)
 
 		if err != nil {
@@ -311,6 +322,7 @@ func (api *UltipaAPI) InsertNodesBatchAuto(nodes []*structs.Node, config *config
 			//Silent:     config.Silent,
 			Silent: true,
 		})
+		api.invalidateGraphCache(conf.CurrentGraph)
 
 		if err != nil {
 			return nil, err
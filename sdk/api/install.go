@@ -0,0 +1,578 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var DefaultInstallChunkSize = 1024 * 1024 // 1MB
+
+// PackageInfo the base info of an algo or exta package, read from its yml file
+type PackageInfo struct {
+	Name        string
+	Version     string // empty if the yml has no version
+	Category    string
+	Description string
+	SoFile      string
+	YmlFile     string
+}
+
+// InstallProgress progress of installing a package, reported after each chunk is sent
+type InstallProgress struct {
+	Package   string
+	File      string // the .so file is sent first, then the .yml file
+	FileSent  uint64
+	FileSize  uint64
+	TotalSent uint64
+	TotalSize uint64
+}
+
+// InstallOptions options of installing algo or exta packages
+type InstallOptions struct {
+	ChunkSize  int                             // default is DefaultInstallChunkSize
+	OnProgress func(progress *InstallProgress) // called after each chunk is sent
+	Force      bool                            // InstallAlgoDir installs the packages not newer than the installed ones as well
+	Config     *configuration.RequestConfig
+}
+
+// InstallDirResult result of InstallAlgoDir
+type InstallDirResult struct {
+	Installed []*PackageInfo // packages not installed before
+	Upgraded  []*PackageInfo // packages installed again, with a different version or by Force
+	Skipped   []*PackageInfo // packages installed already with the same or a newer version
+}
+
+// ParsePackageInfo parses the yml file of a package, which must have a base section with name or name_en
+func ParsePackageInfo(yml []byte) (*PackageInfo, error) {
+	doc := map[string]interface{}{}
+	if err := yaml.Unmarshal(yml, &doc); err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid package yml: %v", err))
+	}
+	base, ok := doc["base"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid package yml: base section is missing")
+	}
+	for _, section := range []string{"param_form", "file_write_form", "db_write_form"} {
+		if value, ok := doc[section]; ok && value != nil {
+			if _, ok := value.(map[string]interface{}); !ok {
+				return nil, errors.New(fmt.Sprintf("invalid package yml: %s is not a mapping", section))
+			}
+		}
+	}
+
+	info := &PackageInfo{
+		Name:        yamlString(base, "name", "name_en"),
+		Version:     yamlString(base, "version"),
+		Category:    yamlString(base, "category"),
+		Description: yamlString(base, "desc", "desc_en"),
+	}
+	if info.Name == "" {
+		return nil, errors.New("invalid package yml: base.name is missing")
+	}
+	return info, nil
+}
+
+func yamlString(values map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := values[key]; ok && value != nil {
+			return strings.TrimSpace(fmt.Sprint(value))
+		}
+	}
+	return ""
+}
+
+// SoPackageName parses the package name and version from a .so file name,
+// e.g. libplugin_lpa.so is lpa, libexta_khop-1.2.0.so is khop of version 1.2.0
+func SoPackageName(soFile string) (name string, version string) {
+	name = strings.TrimSuffix(path.Base(filepath.ToSlash(soFile)), ".so")
+	name = strings.TrimPrefix(name, "lib")
+	for _, prefix := range []string{"plugin_", "exta_"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	if dash := strings.LastIndex(name, "-"); dash > 0 {
+		name, version = name[:dash], name[dash+1:]
+	}
+	return name, version
+}
+
+// CheckPackageFiles checks the .so file belongs to the package of info, by name and by version if the .so file name has one
+func CheckPackageFiles(soFile string, info *PackageInfo) error {
+	if !strings.HasSuffix(soFile, ".so") {
+		return errors.New(fmt.Sprintf("%s is not a .so file", soFile))
+	}
+	name, version := SoPackageName(soFile)
+	if !strings.EqualFold(name, info.Name) {
+		return errors.New(fmt.Sprintf("%s does not match package %s of %s", soFile, info.Name, info.YmlFile))
+	}
+	if version != "" && info.Version != "" && version != info.Version {
+		return errors.New(fmt.Sprintf("version %s of %s does not match version %s of %s", version, soFile, info.Version, info.YmlFile))
+	}
+	return nil
+}
+
+// CompareVersion compares dotted versions by number, e.g. 1.10.0 > 1.9, returns -1, 0 or 1
+func CompareVersion(a string, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xErr := strconv.Atoi(x)
+		yn, yErr := strconv.Atoi(y)
+		if x == "" {
+			xn, xErr = 0, nil
+		}
+		if y == "" {
+			yn, yErr = 0, nil
+		}
+		if xErr == nil && yErr == nil {
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+		if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// packageFile a file of a package, open is called for each pass over the file
+type packageFile struct {
+	name string
+	size uint64
+	open func() (io.ReadCloser, error)
+}
+
+func localPackageFile(filePath string) (*packageFile, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, errors.New(fmt.Sprintf("%s is a directory", filePath))
+	}
+	return &packageFile{
+		name: filepath.Base(filePath),
+		size: uint64(info.Size()),
+		open: func() (io.ReadCloser, error) {
+			return os.Open(filePath)
+		},
+	}, nil
+}
+
+func fsPackageFile(fsys fs.FS, name string) (*packageFile, error) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, errors.New(fmt.Sprintf("%s is a directory", name))
+	}
+	return &packageFile{
+		name: path.Base(name),
+		size: uint64(info.Size()),
+		open: func() (io.ReadCloser, error) {
+			return fsys.Open(name)
+		},
+	}, nil
+}
+
+// readerPackageFile reads r twice by seeking if it is an io.ReadSeeker, otherwise r is read into memory
+func readerPackageFile(name string, r io.Reader) (*packageFile, error) {
+	seeker, ok := r.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		seeker = bytes.NewReader(data)
+	}
+	size, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	return &packageFile{
+		name: name,
+		size: uint64(size),
+		open: func() (io.ReadCloser, error) {
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return io.NopCloser(seeker), nil
+		},
+	}, nil
+}
+
+func (f *packageFile) readAll() ([]byte, error) {
+	r, err := f.open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func (f *packageFile) md5() (string, error) {
+	r, err := f.open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	hash := md5.New()
+	if _, err = io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// InstallAlgoFromReader installs an algo package from readers, so and yml are checked before uploading
+func (api *UltipaAPI) InstallAlgoFromReader(ctx context.Context, soName string, so io.Reader, ymlName string, yml io.Reader, opts *InstallOptions) (*PackageInfo, error) {
+	return api.installFromReader(ctx, "algo", soName, so, ymlName, yml, opts)
+}
+
+// InstallAlgoFS installs an algo package of the .so and .yml files in fsys
+func (api *UltipaAPI) InstallAlgoFS(ctx context.Context, fsys fs.FS, soFile string, ymlFile string, opts *InstallOptions) (*PackageInfo, error) {
+	return api.installFromFS(ctx, "algo", fsys, soFile, ymlFile, opts)
+}
+
+// InstallExtaFromReader installs an exta package from readers, so and yml are checked before uploading
+func (api *UltipaAPI) InstallExtaFromReader(ctx context.Context, soName string, so io.Reader, ymlName string, yml io.Reader, opts *InstallOptions) (*PackageInfo, error) {
+	return api.installFromReader(ctx, "exta", soName, so, ymlName, yml, opts)
+}
+
+// InstallExtaFS installs an exta package of the .so and .yml files in fsys
+func (api *UltipaAPI) InstallExtaFS(ctx context.Context, fsys fs.FS, soFile string, ymlFile string, opts *InstallOptions) (*PackageInfo, error) {
+	return api.installFromFS(ctx, "exta", fsys, soFile, ymlFile, opts)
+}
+
+func (api *UltipaAPI) installFromReader(ctx context.Context, kind string, soName string, so io.Reader, ymlName string, yml io.Reader, opts *InstallOptions) (*PackageInfo, error) {
+	soFile, err := readerPackageFile(soName, so)
+	if err != nil {
+		return nil, err
+	}
+	ymlFile, err := readerPackageFile(ymlName, yml)
+	if err != nil {
+		return nil, err
+	}
+	return api.installPackageChecked(ctx, kind, soFile, ymlFile, opts)
+}
+
+func (api *UltipaAPI) installFromFS(ctx context.Context, kind string, fsys fs.FS, soName string, ymlName string, opts *InstallOptions) (*PackageInfo, error) {
+	soFile, err := fsPackageFile(fsys, soName)
+	if err != nil {
+		return nil, err
+	}
+	ymlFile, err := fsPackageFile(fsys, ymlName)
+	if err != nil {
+		return nil, err
+	}
+	return api.installPackageChecked(ctx, kind, soFile, ymlFile, opts)
+}
+
+// installPackageChecked installs the package and fails if server replies a failed status
+func (api *UltipaAPI) installPackageChecked(ctx context.Context, kind string, so *packageFile, yml *packageFile, opts *InstallOptions) (*PackageInfo, error) {
+	info, status, err := api.installPackage(ctx, kind, so, yml, opts)
+	if err != nil {
+		return nil, err
+	}
+	if status != nil && status.ErrorCode != ultipa.ErrorCode_SUCCESS {
+		return nil, errors.New(fmt.Sprintf("failed to install %s %s: %s", kind, info.Name, status.Msg))
+	}
+	return info, nil
+}
+
+// checkPackage reads the package info from yml and checks so belongs to it
+func checkPackage(so *packageFile, yml *packageFile) (*PackageInfo, error) {
+	ymlData, err := yml.readAll()
+	if err != nil {
+		return nil, err
+	}
+	info, err := ParsePackageInfo(ymlData)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("%s: %v", yml.name, err))
+	}
+	info.SoFile = so.name
+	info.YmlFile = yml.name
+	if err = CheckPackageFiles(so.name, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// installPackage checks the .so and .yml files of the package, then sends them by sendPackage
+func (api *UltipaAPI) installPackage(ctx context.Context, kind string, so *packageFile, yml *packageFile, opts *InstallOptions) (*PackageInfo, *ultipa.Status, error) {
+	info, err := checkPackage(so, yml)
+	if err != nil {
+		return nil, nil, err
+	}
+	status, err := api.sendPackage(ctx, kind, info.Name, so, yml, opts)
+	return info, status, err
+}
+
+// requestContext returns the Context of config, or context.Background() if it is not set
+func requestContext(config *configuration.RequestConfig) context.Context {
+	if config != nil && config.Context != nil {
+		return config.Context
+	}
+	return context.Background()
+}
+
+// sendPackage sends the .so and .yml files by the InstallAlgo or InstallExta stream without checking them,
+// packageName is reported in the progress
+func (api *UltipaAPI) sendPackage(ctx context.Context, kind string, packageName string, so *packageFile, yml *packageFile, opts *InstallOptions) (*ultipa.Status, error) {
+	if opts == nil {
+		opts = &InstallOptions{}
+	}
+
+	soMD5, err := so.md5()
+	if err != nil {
+		return nil, err
+	}
+	ymlMD5, err := yml.md5()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &configuration.RequestConfig{}
+	if opts.Config != nil {
+		copied := *opts.Config
+		config = &copied
+	}
+	if ctx != nil {
+		config.Context = ctx
+	}
+	operation := OperationInstallAlgo
+	if kind == "exta" {
+		if err = api.requireFeature(FeatureExta); err != nil {
+			return nil, err
+		}
+		operation = OperationInstallExta
+	}
+	if err = api.preflight(operation, "", config); err != nil {
+		return nil, err
+	}
+
+	client, err := api.GetControlClient(config)
+	if err != nil {
+		return nil, err
+	}
+	streamCtx, cancel, err := api.Pool.NewContext(config)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	var send func(fileName string, md5 string, chunk []byte) error
+	var closeAndRecv func() (*ultipa.Status, error)
+	if kind == "exta" {
+		stream, err := client.InstallExta(streamCtx)
+		if err != nil {
+			return nil, err
+		}
+		send = func(fileName string, md5 string, chunk []byte) error {
+			return stream.Send(&ultipa.InstallExtaRequest{FileName: fileName, Md5: md5, Chunk: chunk})
+		}
+		closeAndRecv = func() (*ultipa.Status, error) {
+			reply, err := stream.CloseAndRecv()
+			if err != nil {
+				return nil, err
+			}
+			return reply.Status, nil
+		}
+	} else {
+		stream, err := client.InstallAlgo(streamCtx)
+		if err != nil {
+			return nil, err
+		}
+		send = func(fileName string, md5 string, chunk []byte) error {
+			return stream.Send(&ultipa.InstallAlgoRequest{FileName: fileName, Md5: md5, Chunk: chunk})
+		}
+		closeAndRecv = func() (*ultipa.Status, error) {
+			reply, err := stream.CloseAndRecv()
+			if err != nil {
+				return nil, err
+			}
+			return reply.Status, nil
+		}
+	}
+
+	progress := &InstallProgress{Package: packageName, TotalSize: so.size + yml.size}
+	for _, file := range []struct {
+		file *packageFile
+		md5  string
+	}{{so, soMD5}, {yml, ymlMD5}} {
+		if err = sendPackageFile(streamCtx, file.file, file.md5, send, progress, opts); err != nil {
+			// the error of the stream is returned by CloseAndRecv
+			if _, recvErr := closeAndRecv(); recvErr != nil {
+				return nil, recvErr
+			}
+			return nil, err
+		}
+	}
+
+	return closeAndRecv()
+}
+
+func sendPackageFile(ctx context.Context, file *packageFile, md5 string, send func(string, string, []byte) error, progress *InstallProgress, opts *InstallOptions) error {
+	r, err := file.open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	progress.File = file.name
+	progress.FileSent = 0
+	progress.FileSize = file.size
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultInstallChunkSize
+	}
+	chunk := make([]byte, chunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := io.ReadFull(r, chunk)
+		if n > 0 {
+			if err := send(file.name, md5, chunk[:n]); err != nil {
+				return err
+			}
+			progress.FileSent += uint64(n)
+			progress.TotalSent += uint64(n)
+			if opts.OnProgress != nil {
+				opts.OnProgress(progress)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// InstallAlgoDir installs the algo packages of the .so and .yml pairs in dir, a package is skipped if ShowAlgo returns
+// the same or a newer version of it, unless opts.Force is set, so that it can be called again to upgrade the packages
+func (api *UltipaAPI) InstallAlgoDir(ctx context.Context, dir string, opts *InstallOptions) (*InstallDirResult, error) {
+	if opts == nil {
+		opts = &InstallOptions{}
+	}
+	fsys := os.DirFS(dir)
+	packages, err := findPackages(fsys)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to read packages in %s: %v", dir, err))
+	}
+
+	algos, err := api.ShowAlgo(opts.Config)
+	if err != nil {
+		return nil, err
+	}
+	installed := map[string]string{}
+	for _, algo := range algos {
+		installed[strings.ToLower(algo.Name)] = algo.Version
+	}
+
+	result := &InstallDirResult{}
+	for _, info := range packages {
+		version, exists := installed[strings.ToLower(info.Name)]
+		if exists && !opts.Force && (info.Version == "" || CompareVersion(version, info.Version) >= 0) {
+			result.Skipped = append(result.Skipped, info)
+			continue
+		}
+		if _, err = api.InstallAlgoFS(ctx, fsys, info.SoFile, info.YmlFile, opts); err != nil {
+			return result, err
+		}
+		if exists {
+			result.Upgraded = append(result.Upgraded, info)
+		} else {
+			result.Installed = append(result.Installed, info)
+		}
+	}
+	return result, nil
+}
+
+// findPackages pairs the .yml files with the .so files in the root of fsys, every file must be paired
+func findPackages(fsys fs.FS) ([]*PackageInfo, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var soFiles, ymlFiles []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch path.Ext(entry.Name()) {
+		case ".so":
+			soFiles = append(soFiles, entry.Name())
+		case ".yml", ".yaml":
+			ymlFiles = append(ymlFiles, entry.Name())
+		}
+	}
+
+	paired := map[string]bool{}
+	var packages []*PackageInfo
+	for _, ymlFile := range ymlFiles {
+		data, err := fs.ReadFile(fsys, ymlFile)
+		if err != nil {
+			return nil, err
+		}
+		info, err := ParsePackageInfo(data)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("%s: %v", ymlFile, err))
+		}
+		info.YmlFile = ymlFile
+		for _, soFile := range soFiles {
+			if name, _ := SoPackageName(soFile); strings.EqualFold(name, info.Name) {
+				if info.SoFile != "" {
+					return nil, errors.New(fmt.Sprintf("%s and %s are both files of package %s", info.SoFile, soFile, info.Name))
+				}
+				info.SoFile = soFile
+			}
+		}
+		if info.SoFile == "" {
+			return nil, errors.New(fmt.Sprintf("no .so file for package %s of %s", info.Name, ymlFile))
+		}
+		if err = CheckPackageFiles(info.SoFile, info); err != nil {
+			return nil, err
+		}
+		paired[info.SoFile] = true
+		packages = append(packages, info)
+	}
+	for _, soFile := range soFiles {
+		if !paired[soFile] {
+			return nil, errors.New(fmt.Sprintf("no .yml file for %s", soFile))
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
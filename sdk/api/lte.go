@@ -0,0 +1,239 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EngineProperty is a property to be loaded to the computing engine, an empty Schema means the property of all schemas
+type EngineProperty struct {
+	DBType   ultipa.DBType
+	Schema   string
+	Property string
+}
+
+func (p *EngineProperty) String() string {
+	schema := p.Schema
+	if schema == "" {
+		schema = "*"
+	}
+	return fmt.Sprintf("%s @%s.%s", structs.DBTypeToString(p.DBType), schema, p.Property)
+}
+
+// LoadToEngine loads @schema.property into the computing engine by LTE(), the returned task can be waited for the loading,
+// task is nil if no LTE task is found, which means the property is loaded already
+func (api *UltipaAPI) LoadToEngine(dbType ultipa.DBType, schemaName string, propertyName string, config *configuration.RequestConfig) (*Task, error) {
+	return api.engineCommand("LTE", dbType, schemaName, propertyName, config)
+}
+
+// UnloadFromEngine unloads @schema.property from the computing engine by UFE(), task is nil if no UFE task is found
+func (api *UltipaAPI) UnloadFromEngine(dbType ultipa.DBType, schemaName string, propertyName string, config *configuration.RequestConfig) (*Task, error) {
+	return api.engineCommand("UFE", dbType, schemaName, propertyName, config)
+}
+
+func (api *UltipaAPI) engineCommand(command string, dbType ultipa.DBType, schemaName string, propertyName string, config *configuration.RequestConfig) (*Task, error) {
+	target, err := dbTypeCommand(dbType, "property")
+	if err != nil {
+		return nil, err
+	}
+	config = copyTaskConfig(config)
+
+	resp, err := checkUQLResponse(api.UQLWithParams(command+"()."+target+"(@$schema.$property)", map[string]interface{}{
+		"schema":   utils.UqlName(schemaName),
+		"property": utils.UqlName(propertyName),
+	}, config))
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to %s %s @%s.%s: %v", command, structs.DBTypeToString(dbType), schemaName, propertyName, err))
+	}
+
+	manager := api.TaskManager()
+	if taskId, err := taskIdFromResponse(resp); err == nil {
+		return manager.Get(taskId, config)
+	}
+
+	// the task id is not returned by some servers, take the latest unfinished task of the command on the same property
+	tasks, err := manager.List(command, "", config)
+	if err != nil {
+		return nil, err
+	}
+	property := &EngineProperty{DBType: dbType, Schema: schemaName, Property: propertyName}
+	var latest *Task
+	var latestId uint64
+	for _, task := range tasks {
+		if task.IsFinished() || !strings.EqualFold(task.Algo, command) || !property.MatchesTaskParams(task.Params) {
+			continue
+		}
+		id, _ := strconv.ParseUint(task.Id, 10, 64)
+		if latest == nil || id > latestId {
+			latest, latestId = task, id
+		}
+	}
+	return latest, nil
+}
+
+// MatchesTaskParams checks whether the params of an LTE or UFE task are of the property,
+// params either have a value of @schema.property, or a schema and a property, e.g. {"schema": "account", "property": "age"}
+func (p *EngineProperty) MatchesTaskParams(params map[string]interface{}) bool {
+	schema, property := "", ""
+	for key, value := range params {
+		for _, str := range algoParamStrings(value) {
+			if !strings.HasPrefix(strings.TrimSpace(str), "@") {
+				continue
+			}
+			if parsed := parseEngineProperty(p.DBType, str); parsed != nil && parsed.Schema == p.Schema && parsed.Property == p.Property {
+				return true
+			}
+		}
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		switch lowerKey := strings.ToLower(key); {
+		case strings.Contains(lowerKey, "schema"):
+			schema = strings.Trim(strings.TrimPrefix(str, "@"), "`")
+		case strings.Contains(lowerKey, "property"):
+			property = strings.Trim(str, "`")
+		}
+	}
+	if schema == "*" {
+		schema = ""
+	}
+	return property != "" && schema == p.Schema && property == p.Property
+}
+
+// AlgoEngineProperties finds the properties referenced by algo params, which are values of params named *property*,
+// e.g. {edge_schema_property: "@transfer.amount"} or {node_schema_property: ["age", "@account.level"]},
+// params named with edge are edge properties, others are node properties
+func AlgoEngineProperties(params map[string]interface{}) []*EngineProperty {
+	var keys []string
+	for key := range params {
+		if strings.Contains(strings.ToLower(key), "property") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var properties []*EngineProperty
+	seen := map[string]bool{}
+	for _, key := range keys {
+		dbType := ultipa.DBType_DBNODE
+		if strings.Contains(strings.ToLower(key), "edge") {
+			dbType = ultipa.DBType_DBEDGE
+		}
+		for _, value := range algoParamStrings(params[key]) {
+			property := parseEngineProperty(dbType, value)
+			if property == nil || seen[property.String()] {
+				continue
+			}
+			seen[property.String()] = true
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
+
+func algoParamStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			values = append(values, algoParamStrings(item)...)
+		}
+		return values
+	}
+	return nil
+}
+
+// parseEngineProperty parses @schema.property, or property of all schemas
+func parseEngineProperty(dbType ultipa.DBType, value string) *EngineProperty {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if !strings.HasPrefix(value, "@") {
+		return &EngineProperty{DBType: dbType, Property: strings.Trim(value, "`")}
+	}
+	dot := strings.LastIndex(value, ".")
+	if dot < 0 {
+		return nil
+	}
+	schema := strings.Trim(value[1:dot], "`")
+	if schema == "*" {
+		schema = ""
+	}
+	return &EngineProperty{DBType: dbType, Schema: schema, Property: strings.Trim(value[dot+1:], "`")}
+}
+
+// FindPropertiesToLoad returns the properties of schemas that are not loaded to engine yet,
+// a property of all schemas is expanded to the schemas having it
+func FindPropertiesToLoad(schemas []*structs.Schema, properties []*EngineProperty) ([]*EngineProperty, error) {
+	var toLoad []*EngineProperty
+	for _, property := range properties {
+		found := false
+		for _, schema := range schemas {
+			if schema.DBType != property.DBType || (property.Schema != "" && schema.Name != property.Schema) {
+				continue
+			}
+			for _, prop := range schema.Properties {
+				if prop.Name != property.Property {
+					continue
+				}
+				found = true
+				if !prop.Lte {
+					toLoad = append(toLoad, &EngineProperty{DBType: property.DBType, Schema: schema.Name, Property: prop.Name})
+				}
+			}
+		}
+		if !found {
+			return nil, errors.New(fmt.Sprintf("property %s is not found", property))
+		}
+	}
+	return toLoad, nil
+}
+
+// EnsureAlgoPropertiesLoaded loads the properties referenced by algo params to engine and waits for the loading,
+// the properties loaded are returned
+func (api *UltipaAPI) EnsureAlgoPropertiesLoaded(ctx context.Context, params map[string]interface{}, config *configuration.RequestConfig) ([]*EngineProperty, error) {
+	properties := AlgoEngineProperties(params)
+	if len(properties) == 0 {
+		return nil, nil
+	}
+
+	var schemas []*structs.Schema
+	for _, dbType := range []ultipa.DBType{ultipa.DBType_DBNODE, ultipa.DBType_DBEDGE} {
+		dbSchemas, err := api.ListSchema(dbType, config)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, dbSchemas...)
+	}
+
+	toLoad, err := FindPropertiesToLoad(schemas, properties)
+	if err != nil {
+		return nil, err
+	}
+	for _, property := range toLoad {
+		task, err := api.LoadToEngine(property.DBType, property.Schema, property.Property, config)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			if err = task.Wait(ctx); err != nil {
+				return nil, errors.New(fmt.Sprintf("failed to load %s to engine: %v", property, err))
+			}
+		}
+	}
+	return toLoad, nil
+}
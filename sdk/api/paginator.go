@@ -0,0 +1,257 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/types"
+	"github.com/ultipa/ultipa-go-sdk/sdk/uqlbuilder"
+)
+
+var DefaultPageSize = 1000
+
+// uuidPaginator walks nodes or edges in _uuid order, each page starts after the last _uuid of the previous page,
+// so deep pages are as fast as the first one, unlike skip and limit
+type uuidPaginator struct {
+	api      *UltipaAPI
+	dbType   ultipa.DBType
+	schema   string
+	filter   uqlbuilder.Filter
+	pageSize int
+	config   *configuration.RequestConfig
+
+	after types.UUID // last _uuid returned
+	done  bool
+}
+
+// paginatorToken is the content of the continuation token
+type paginatorToken struct {
+	Version int        `json:"v"`
+	Type    string     `json:"t"`
+	Graph   string     `json:"g,omitempty"`
+	Schema  string     `json:"s,omitempty"`
+	Filter  string     `json:"f,omitempty"` // digest of the filter uql
+	After   types.UUID `json:"a"`
+	Done    bool       `json:"d,omitempty"`
+}
+
+// NodePaginator returns pages of nodes, check NewNodePaginator
+type NodePaginator struct {
+	uuidPaginator
+}
+
+// EdgePaginator returns pages of edges, check NewEdgePaginator
+type EdgePaginator struct {
+	uuidPaginator
+}
+
+// NewNodePaginator walks the nodes of schema matching filter by pages, schema and filter are optional
+// Usage: p := NewNodePaginator("account", uqlbuilder.Gt("age", 18), 1000, nil); for p.HasNext() { nodes, err := p.Next() }
+func (api *UltipaAPI) NewNodePaginator(schema string, filter uqlbuilder.Filter, pageSize int, config *configuration.RequestConfig) *NodePaginator {
+	return &NodePaginator{newUuidPaginator(api, ultipa.DBType_DBNODE, schema, filter, pageSize, config)}
+}
+
+// NewEdgePaginator walks the edges of schema matching filter by pages, schema and filter are optional
+func (api *UltipaAPI) NewEdgePaginator(schema string, filter uqlbuilder.Filter, pageSize int, config *configuration.RequestConfig) *EdgePaginator {
+	return &EdgePaginator{newUuidPaginator(api, ultipa.DBType_DBEDGE, schema, filter, pageSize, config)}
+}
+
+func newUuidPaginator(api *UltipaAPI, dbType ultipa.DBType, schema string, filter uqlbuilder.Filter, pageSize int, config *configuration.RequestConfig) uuidPaginator {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return uuidPaginator{
+		api:      api,
+		dbType:   dbType,
+		schema:   schema,
+		filter:   filter,
+		pageSize: pageSize,
+		config:   config,
+	}
+}
+
+// Next returns the next page of nodes, an empty page is returned when there are no more nodes
+func (p *NodePaginator) Next() ([]*structs.Node, error) {
+	item, err := p.nextPage()
+	if err != nil || item == nil {
+		return nil, err
+	}
+	nodes, _, err := item.AsNodes()
+	if err != nil {
+		return nil, err
+	}
+	var uuids []types.UUID
+	for _, node := range nodes {
+		uuids = append(uuids, node.UUID)
+	}
+	p.advance(uuids)
+	return nodes, nil
+}
+
+// Next returns the next page of edges, an empty page is returned when there are no more edges
+func (p *EdgePaginator) Next() ([]*structs.Edge, error) {
+	item, err := p.nextPage()
+	if err != nil || item == nil {
+		return nil, err
+	}
+	edges, _, err := item.AsEdges()
+	if err != nil {
+		return nil, err
+	}
+	var uuids []types.UUID
+	for _, edge := range edges {
+		uuids = append(uuids, edge.UUID)
+	}
+	p.advance(uuids)
+	return edges, nil
+}
+
+// HasNext returns false once a page smaller than the page size is returned
+func (p *uuidPaginator) HasNext() bool {
+	return !p.done
+}
+
+func (p *uuidPaginator) alias() string {
+	if p.dbType == ultipa.DBType_DBEDGE {
+		return "edges"
+	}
+	return "nodes"
+}
+
+// Uql returns the uql of the next page
+func (p *uuidPaginator) Uql() (string, error) {
+	var filters []uqlbuilder.Filter
+	if p.schema != "" {
+		filters = append(filters, uqlbuilder.Schema(p.schema))
+	}
+	if p.after > 0 {
+		filters = append(filters, uqlbuilder.Gt("_uuid", p.after))
+	}
+	if p.filter != nil {
+		filters = append(filters, p.filter)
+	}
+	var filter uqlbuilder.Filter
+	if len(filters) > 0 {
+		filter = uqlbuilder.And(filters...)
+	}
+
+	builder := uqlbuilder.New()
+	if p.dbType == ultipa.DBType_DBEDGE {
+		builder.FindEdges(filter)
+	} else {
+		builder.FindNodes(filter)
+	}
+	alias := p.alias()
+	return builder.As(alias).OrderBy(alias + "._uuid").Limit(p.pageSize).Return(alias + "{*}").Build()
+}
+
+func (p *uuidPaginator) nextPage() (*http.DataItem, error) {
+	if p.done {
+		return nil, nil
+	}
+	uql, err := p.Uql()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.api.UQL(uql, p.config)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, errors.New(fmt.Sprintf("failed to fetch page: %s", resp.Status.Message))
+	}
+	return resp.Alias(p.alias()), nil
+}
+
+// advance moves the cursor after the uuids of the returned page
+func (p *uuidPaginator) advance(uuids []types.UUID) {
+	for _, uuid := range uuids {
+		if uuid > p.after {
+			p.after = uuid
+		}
+	}
+	if len(uuids) < p.pageSize {
+		p.done = true
+	}
+}
+
+func (p *uuidPaginator) graph() string {
+	if p.config != nil && p.config.GraphName != "" {
+		return p.config.GraphName
+	}
+	if p.api != nil && p.api.Config != nil {
+		return p.api.Config.CurrentGraph
+	}
+	return ""
+}
+
+func (p *uuidPaginator) filterDigest() (string, error) {
+	if p.filter == nil {
+		return "", nil
+	}
+	uql, err := p.filter.Uql()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(uql))
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+func (p *uuidPaginator) tokenContent() (*paginatorToken, error) {
+	digest, err := p.filterDigest()
+	if err != nil {
+		return nil, err
+	}
+	return &paginatorToken{
+		Version: 1,
+		Type:    p.alias(),
+		Graph:   p.graph(),
+		Schema:  p.schema,
+		Filter:  digest,
+	}, nil
+}
+
+// Token returns an opaque continuation token of the current position, pass it to Resume of a paginator with the same graph, schema and filter to continue
+func (p *uuidPaginator) Token() (string, error) {
+	token, err := p.tokenContent()
+	if err != nil {
+		return "", err
+	}
+	token.After = p.after
+	token.Done = p.done
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Resume moves the paginator to the position of token, token must be created by a paginator with the same graph, schema and filter
+func (p *uuidPaginator) Resume(token string) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return errors.New("invalid paginator token: " + err.Error())
+	}
+	var resumed paginatorToken
+	if err = json.Unmarshal(data, &resumed); err != nil {
+		return errors.New("invalid paginator token: " + err.Error())
+	}
+	expected, err := p.tokenContent()
+	if err != nil {
+		return err
+	}
+	if resumed.Version != expected.Version || resumed.Type != expected.Type || resumed.Graph != expected.Graph ||
+		resumed.Schema != expected.Schema || resumed.Filter != expected.Filter {
+		return errors.New(fmt.Sprintf("paginator token of %s, graph %s, schema %s does not match the paginator, or the filter is changed", resumed.Type, resumed.Graph, resumed.Schema))
+	}
+	p.after = resumed.After
+	p.done = resumed.Done
+	return nil
+}
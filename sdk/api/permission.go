@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"sync"
+	"time"
+)
+
+// Operation an sdk operation whose permission is checked by CanI
+type Operation string
+
+const (
+	OperationUQL                   Operation = "UQL"
+	OperationInsertNodes           Operation = "InsertNodes"
+	OperationInsertEdges           Operation = "InsertEdges"
+	OperationExport                Operation = "Export"
+	OperationDownloadFile          Operation = "DownloadFile"
+	OperationInstallAlgo           Operation = "InstallAlgo"
+	OperationUninstallAlgo         Operation = "UninstallAlgo"
+	OperationUploader              Operation = "Uploader"
+	OperationCreateGraphByUploader Operation = "CreateGraphByUploader"
+	OperationInstallExta           Operation = "InstallExta"
+	OperationUninstallExta         Operation = "UninstallExta"
+)
+
+var operationAuthenticateTypes = map[Operation]ultipa.AuthenticateType{
+	OperationUQL:                   ultipa.AuthenticateType_PERMISSION_TYPE_UQL,
+	OperationInsertNodes:           ultipa.AuthenticateType_PERMISSION_TYPE_INSERTNODES,
+	OperationInsertEdges:           ultipa.AuthenticateType_PERMISSION_TYPE_INSERTEDGES,
+	OperationExport:                ultipa.AuthenticateType_PERMISSION_TYPE_EXPORT,
+	OperationDownloadFile:          ultipa.AuthenticateType_PERMISSION_TYPE_DOWNLOADFILE,
+	OperationInstallAlgo:           ultipa.AuthenticateType_PERMISSION_TYPE_INSTALLALGO,
+	OperationUninstallAlgo:         ultipa.AuthenticateType_PERMISSION_TYPE_UNINSTALLALGO,
+	OperationUploader:              ultipa.AuthenticateType_PERMISSION_TYPE_UPLOADER,
+	OperationCreateGraphByUploader: ultipa.AuthenticateType_PERMISSION_TYPE_CREATEGRAPHBYUPLOADER,
+	OperationInstallExta:           ultipa.AuthenticateType_PERMISSION_TYPE_INSTALLEXTA,
+	OperationUninstallExta:         ultipa.AuthenticateType_PERMISSION_TYPE_UNINSTALLEXTA,
+}
+
+// AuthenticateType returns the type of the Authenticate rpc checking the operation
+func (o Operation) AuthenticateType() (ultipa.AuthenticateType, error) {
+	authenticateType, ok := operationAuthenticateTypes[o]
+	if !ok {
+		return 0, errors.New(fmt.Sprintf("unknown operation to authenticate: %s", o))
+	}
+	return authenticateType, nil
+}
+
+var DefaultPermissionCacheTTL = time.Minute
+var DefaultPermissionCacheSize = 1000
+
+// PermissionCache caches the results of CanI by user, graph, operation and uql, entries expire after TTL,
+// expired entries are swept and the oldest entry is evicted when Size is reached,
+// it is cleared when a global write uql, e.g. grant() or revoke(), is sent by the client
+type PermissionCache struct {
+	Size int           // max entries
+	TTL  time.Duration // 0 means entries never expire
+
+	lock    sync.Mutex
+	entries map[string]*permissionCacheEntry
+}
+
+type permissionCacheEntry struct {
+	allowed   bool
+	message   string
+	createdAt time.Time
+	expireAt  time.Time
+}
+
+func NewPermissionCache(ttl time.Duration) *PermissionCache {
+	return &PermissionCache{
+		Size:    DefaultPermissionCacheSize,
+		TTL:     ttl,
+		entries: map[string]*permissionCacheEntry{},
+	}
+}
+
+func permissionCacheKey(user string, graph string, operation Operation, uql string) string {
+	return user + "\x00" + graph + "\x00" + string(operation) + "\x00" + utils.NormalizeUql(uql)
+}
+
+func (c *PermissionCache) get(key string) (*permissionCacheEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !entry.expireAt.IsZero() && time.Now().After(entry.expireAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry, true
+}
+
+func (c *PermissionCache) set(key string, allowed bool, message string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	entry := &permissionCacheEntry{allowed: allowed, message: message, createdAt: now}
+	if c.TTL > 0 {
+		entry.expireAt = now.Add(c.TTL)
+	}
+	if _, ok := c.entries[key]; !ok && c.Size > 0 && len(c.entries) >= c.Size {
+		c.sweep(now)
+		if len(c.entries) >= c.Size {
+			c.evictOldest()
+		}
+	}
+	c.entries[key] = entry
+}
+
+// sweep removes the expired entries
+func (c *PermissionCache) sweep(now time.Time) {
+	for key, entry := range c.entries {
+		if !entry.expireAt.IsZero() && now.After(entry.expireAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *PermissionCache) evictOldest() {
+	oldestKey := ""
+	var oldest *permissionCacheEntry
+	for key, entry := range c.entries {
+		if oldest == nil || entry.createdAt.Before(oldest.createdAt) {
+			oldestKey, oldest = key, entry
+		}
+	}
+	if oldest != nil {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// Len returns the count of the cached results
+func (c *PermissionCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.entries)
+}
+
+// Clear removes all the cached results
+func (c *PermissionCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = map[string]*permissionCacheEntry{}
+}
+
+// CanI checks whether the current user has the permission of operation on the graph of config by the Authenticate rpc,
+// uql is the uql of OperationUQL, and is empty for other operations. Results are cached by user and graph, see PermissionCache
+// Usage: allowed, err := CanI(ctx, OperationUQL, "insert().into(@account).nodes({name: 'Alice'})", nil)
+func (api *UltipaAPI) CanI(ctx context.Context, operation Operation, uql string, config *configuration.RequestConfig) (bool, error) {
+	allowed, _, err := api.authenticateOperation(ctx, operation, uql, config)
+	return allowed, err
+}
+
+// authenticateOperation returns whether operation is allowed and the message replied by server if it is denied
+func (api *UltipaAPI) authenticateOperation(ctx context.Context, operation Operation, uql string, config *configuration.RequestConfig) (bool, string, error) {
+	authenticateType, err := operation.AuthenticateType()
+	if err != nil {
+		return false, "", err
+	}
+
+	authConfig := &configuration.RequestConfig{}
+	if config != nil {
+		copied := *config
+		authConfig = &copied
+	}
+	if ctx != nil {
+		authConfig.Context = ctx
+	}
+
+	key := permissionCacheKey(api.Config.Username, api.permissionGraph(authConfig), operation, uql)
+	if api.Permissions != nil {
+		if entry, ok := api.Permissions.get(key); ok {
+			return entry.allowed, entry.message, nil
+		}
+	}
+
+	reply, err := api.Authenticate(authenticateType, uql, authConfig)
+	if err != nil {
+		return false, "", err
+	}
+	allowed := true
+	message := ""
+	if reply.Status != nil {
+		switch reply.Status.ErrorCode {
+		case ultipa.ErrorCode_SUCCESS:
+		case ultipa.ErrorCode_PERMISSION_DENIED:
+			allowed, message = false, reply.Status.Msg
+		default:
+			return false, "", errors.New(fmt.Sprintf("failed to authenticate %s: %s", operation, reply.Status.Msg))
+		}
+	}
+	if api.Permissions != nil {
+		api.Permissions.set(key, allowed, message)
+	}
+	return allowed, message, nil
+}
+
+func (api *UltipaAPI) permissionGraph(config *configuration.RequestConfig) string {
+	if config != nil && config.GraphName != "" {
+		return config.GraphName
+	}
+	return api.Config.CurrentGraph
+}
+
+// preflight checks the permission of operation by CanI if config.Preflight is set, and returns a utils.PermissionDeniedError if it is denied
+func (api *UltipaAPI) preflight(operation Operation, uql string, config *configuration.RequestConfig) error {
+	if config == nil || !config.Preflight {
+		return nil
+	}
+	allowed, message, err := api.authenticateOperation(config.Context, operation, uql, config)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return utils.NewPermissionDeniedError(api.Config.Username, api.permissionGraph(config), string(operation), uql, message)
+	}
+	return nil
+}
+
+// invalidatePermissionCache clears the cached permissions when privileges may be changed by a global write uql
+func (api *UltipaAPI) invalidatePermissionCache(uqlItem *utils.UqlItem) {
+	if api.Permissions != nil && uqlItem.IsGlobal() && uqlItem.HasWrite() {
+		api.Permissions.Clear()
+	}
+}
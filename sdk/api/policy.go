@@ -0,0 +1,189 @@
+package api
+
+import (
+	"errors"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+)
+
+// ListPrivileges lists all the graph, system and property privileges by show().privilege()
+func (api *UltipaAPI) ListPrivileges(config *configuration.RequestConfig) ([]*structs.Privilege, error) {
+	resp, err := api.UQL("show().privilege()", config)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, errors.New(resp.Status.Message)
+	}
+	return resp.Alias(http.RESP_PRIVILEGE_KEY).AsPrivileges()
+}
+
+func (api *UltipaAPI) ListPolicies(config *configuration.RequestConfig) ([]*structs.Policy, error) {
+	resp, err := api.UQL("show().policy()", config)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, errors.New(resp.Status.Message)
+	}
+	return resp.Alias(http.RESP_POLICY_KEY).AsPolicies()
+}
+
+func (api *UltipaAPI) GetPolicy(name string, config *configuration.RequestConfig) (*structs.Policy, error) {
+	resp, err := api.UQLWithParams("show().policy($name)", map[string]interface{}{
+		"name": name,
+	}, config)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, errors.New(resp.Status.Message)
+	}
+	policies, err := resp.Alias(http.RESP_POLICY_KEY).AsPolicies()
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if policy.Name == name {
+			return policy, nil
+		}
+	}
+	return nil, errors.New("policy not found: " + name)
+}
+
+// CreatePolicy creates policy with its privileges and nested policies
+func (api *UltipaAPI) CreatePolicy(policy *structs.Policy, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	if err := api.requirePropertyPrivileges(policy.PrivilegeSet()); err != nil {
+		return nil, err
+	}
+	params, err := privilegeParams(policy.PrivilegeSet())
+	if err != nil {
+		return nil, err
+	}
+	uql := "create().policy($name)"
+	if len(params) > 0 {
+		uql += ".params($params)"
+	}
+	return checkUQLResponse(api.UQLWithParams(uql, map[string]interface{}{
+		"name":   policy.Name,
+		"params": params,
+	}, config))
+}
+
+// AlterPolicy replaces the privileges and nested policies which are not nil
+func (api *UltipaAPI) AlterPolicy(policy *structs.Policy, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	if err := api.requirePropertyPrivileges(policy.PrivilegeSet()); err != nil {
+		return nil, err
+	}
+	values, err := privilegeParams(policy.PrivilegeSet())
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, errors.New("nothing to alter for policy " + policy.Name)
+	}
+	return checkUQLResponse(api.UQLWithParams("alter().policy($name).set($values)", map[string]interface{}{
+		"name":   policy.Name,
+		"values": values,
+	}, config))
+}
+
+func (api *UltipaAPI) DropPolicy(name string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	return checkUQLResponse(api.UQLWithParams("drop().policy($name)", map[string]interface{}{
+		"name": name,
+	}, config))
+}
+
+// GrantToPolicy grants privileges and nested policies to policy, nil fields are skipped
+func (api *UltipaAPI) GrantToPolicy(name string, privileges *structs.PrivilegeSet, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	return api.grantOrRevoke("grant", "policy", name, privileges, config)
+}
+
+// RevokeFromPolicy revokes privileges and nested policies from policy, nil fields are skipped
+func (api *UltipaAPI) RevokeFromPolicy(name string, privileges *structs.PrivilegeSet, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	return api.grantOrRevoke("revoke", "policy", name, privileges, config)
+}
+
+// DiffUserUql returns the revoke() and grant() uql to move the privileges and policies of current to desired,
+// nil fields of desired are left as they are, no uql is returned if nothing changes
+func DiffUserUql(current *structs.User, desired *structs.User) ([]string, error) {
+	var currentSet *structs.PrivilegeSet
+	if current != nil {
+		currentSet = current.PrivilegeSet()
+	}
+	return diffPrivilegeUql("user", desired.Username, currentSet, desired.PrivilegeSet())
+}
+
+// DiffPolicyUql returns the revoke() and grant() uql to move the privileges and nested policies of current to desired,
+// nil fields of desired are left as they are, no uql is returned if nothing changes
+func DiffPolicyUql(current *structs.Policy, desired *structs.Policy) ([]string, error) {
+	var currentSet *structs.PrivilegeSet
+	if current != nil {
+		currentSet = current.PrivilegeSet()
+	}
+	return diffPrivilegeUql("policy", desired.Name, currentSet, desired.PrivilegeSet())
+}
+
+func diffPrivilegeUql(target string, name string, current *structs.PrivilegeSet, desired *structs.PrivilegeSet) ([]string, error) {
+	grant, revoke := structs.DiffPrivilegeSets(current, desired)
+	var uqls []string
+	if !revoke.IsEmpty() {
+		uql, err := grantOrRevokeUql("revoke", target, name, revoke)
+		if err != nil {
+			return nil, err
+		}
+		uqls = append(uqls, uql)
+	}
+	if !grant.IsEmpty() {
+		uql, err := grantOrRevokeUql("grant", target, name, grant)
+		if err != nil {
+			return nil, err
+		}
+		uqls = append(uqls, uql)
+	}
+	return uqls, nil
+}
+
+// SyncUserPrivileges moves the privileges and policies of the existing user to desired, the uql run are returned
+func (api *UltipaAPI) SyncUserPrivileges(desired *structs.User, config *configuration.RequestConfig) ([]string, error) {
+	if err := api.requirePropertyPrivileges(desired.PrivilegeSet()); err != nil {
+		return nil, err
+	}
+	current, err := api.GetUser(desired.Username, config)
+	if err != nil {
+		return nil, err
+	}
+	uqls, err := DiffUserUql(current, desired)
+	if err != nil {
+		return nil, err
+	}
+	return api.runPrivilegeUql(uqls, config)
+}
+
+// SyncPolicyPrivileges moves the privileges and nested policies of the existing policy to desired, the uql run are returned
+func (api *UltipaAPI) SyncPolicyPrivileges(desired *structs.Policy, config *configuration.RequestConfig) ([]string, error) {
+	if err := api.requirePropertyPrivileges(desired.PrivilegeSet()); err != nil {
+		return nil, err
+	}
+	current, err := api.GetPolicy(desired.Name, config)
+	if err != nil {
+		return nil, err
+	}
+	uqls, err := DiffPolicyUql(current, desired)
+	if err != nil {
+		return nil, err
+	}
+	return api.runPrivilegeUql(uqls, config)
+}
+
+// runPrivilegeUql runs uql in order and stops at the first failure, the uql succeeded are returned
+func (api *UltipaAPI) runPrivilegeUql(uqls []string, config *configuration.RequestConfig) ([]string, error) {
+	for i, uql := range uqls {
+		if _, err := checkUQLResponse(api.UQL(uql, config)); err != nil {
+			return uqls[:i], errors.New("failed to run " + utils.NormalizeUql(uql) + ": " + err.Error())
+		}
+	}
+	return uqls, nil
+}
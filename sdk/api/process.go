@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils/logger"
+	"sync"
+)
+
+// ListProcesses lists the running uql by top()
+func (api *UltipaAPI) ListProcesses(config *configuration.RequestConfig) ([]*structs.Process, error) {
+	resp, err := api.UQL("top()", config)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, errors.New(resp.Status.Message)
+	}
+	return resp.Alias(http.RESP_TOP_KEY).AsProcesses()
+}
+
+// KillProcess kills a running uql by its process id
+func (api *UltipaAPI) KillProcess(processId string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	if processId == "" {
+		return nil, errors.New("process id can not be empty")
+	}
+	return api.UQLWithParams("kill($id)", map[string]interface{}{"id": processId}, config)
+}
+
+// KillAllProcesses kills all the running uql
+func (api *UltipaAPI) KillAllProcesses(config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	return api.UQL(`kill("*")`, config)
+}
+
+// FilterProcessesByUql returns the processes running uql, uql are compared after NormalizeUql
+func FilterProcessesByUql(processes []*structs.Process, uql string) []*structs.Process {
+	normalized := utils.NormalizeUql(uql)
+	var matched []*structs.Process
+	for _, process := range processes {
+		if utils.NormalizeUql(process.Uql) == normalized {
+			matched = append(matched, process)
+		}
+	}
+	return matched
+}
+
+// killOnCancel wraps cancel of a request sent to host, the processes running uql are listed by top() before the request is sent,
+// if parent is cancelled before the request is finished, the new process running uql is found by top() and killed, so server stops computing.
+// If the processes can not be listed before sending, cancel is returned as it is, and nothing is killed
+func (api *UltipaAPI) killOnCancel(parent context.Context, uql string, graph string, host string, cancel context.CancelFunc) context.CancelFunc {
+	config := &configuration.RequestConfig{GraphName: graph, Host: host}
+	before, err := api.ListProcesses(config)
+	if err != nil {
+		logger.PrintWarn(fmt.Sprintf("kill on cancel is turned off, failed to list processes before sending the uql: %v", err))
+		return cancel
+	}
+	existing := map[string]bool{}
+	for _, process := range FilterProcessesByUql(before, uql) {
+		existing[process.Id] = true
+	}
+
+	finished := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		select {
+		case <-finished:
+		case <-parent.Done():
+			api.killUql(uql, config, existing)
+		}
+	}()
+
+	return func() {
+		once.Do(func() {
+			close(finished)
+		})
+		cancel()
+	}
+}
+
+// killUql kills the process running uql which is not in existing, the processes running uql before it is sent.
+// If there is not exactly one such process, e.g. the same uql is sent by another client at the same time, nothing is killed
+func (api *UltipaAPI) killUql(uql string, config *configuration.RequestConfig, existing map[string]bool) {
+	processes, err := api.ListProcesses(config)
+	if err != nil {
+		logger.PrintWarn(fmt.Sprintf("failed to list processes to kill the cancelled uql: %v", err))
+		return
+	}
+	var started []*structs.Process
+	for _, process := range FilterProcessesByUql(processes, uql) {
+		if !existing[process.Id] {
+			started = append(started, process)
+		}
+	}
+	if len(started) != 1 {
+		logger.PrintWarn(fmt.Sprintf("skip killing the cancelled uql, %d new processes are running it: %s", len(started), uql))
+		return
+	}
+
+	process := started[0]
+	resp, err := api.KillProcess(process.Id, config)
+	if err == nil && !resp.IsSuccess() {
+		err = errors.New(resp.Status.Message)
+	}
+	if err != nil {
+		logger.PrintWarn(fmt.Sprintf("failed to kill process %s of the cancelled uql: %v", process.Id, err))
+		return
+	}
+	api.Logger.Log(fmt.Sprintf("killed process %s of the cancelled uql: %s", process.Id, uql))
+}
@@ -8,9 +8,10 @@ import (
 	"github.com/ultipa/ultipa-go-sdk/sdk/http"
 	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
 	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"strings"
 )
 
-//CreateProperty create property for schema, schemaName maybe escaped if schemaName contains some special characters.
+//CreateProperty create property for schema, schemaName will be escaped if schemaName contains some special characters.
 func (api *UltipaAPI) CreateProperty(schemaName string, dbType ultipa.DBType, prop *structs.Property, conf *configuration.RequestConfig) (resp *http.UQLResponse, err error) {
 	err = CheckName(schemaName)
 	if err != nil {
@@ -21,12 +22,8 @@ func (api *UltipaAPI) CreateProperty(schemaName string, dbType ultipa.DBType, pr
 	if err != nil {
 		return nil, errors.New(fmt.Sprintf("%s, propertyName = %s", err.Error(), prop.Name))
 	}
-	escapedSchemaName := schemaName
-	if utils.IsNeedToEscapeSchemaNameForProperty(schemaName) {
-		escapedSchemaName = fmt.Sprintf("`%v`", schemaName)
-	}
 
-	return api.doCreateProperty(escapedSchemaName, dbType, prop, conf)
+	return api.doCreateProperty(schemaName, dbType, prop, conf)
 }
 
 func (api *UltipaAPI) doCreateProperty(schemaName string, dbType ultipa.DBType, prop *structs.Property, conf *configuration.RequestConfig) (resp *http.UQLResponse, err error) {
@@ -119,12 +116,7 @@ func (api *UltipaAPI) CreateNodeProperty(schemaName string, prop *structs.Proper
 	if err != nil {
 		return nil, errors.New(fmt.Sprintf("%s, propertyName = %s", err.Error(), prop.Name))
 	}
-	escapeSchemaName := schemaName
-	if utils.IsNeedToEscapeSchemaNameForProperty(schemaName) {
-		escapeSchemaName = fmt.Sprintf("`%v`", schemaName)
-	}
-
-	return api.doCreateNodeProperty(escapeSchemaName, prop, conf)
+	return api.doCreateNodeProperty(schemaName, prop, conf)
 }
 
 func (api *UltipaAPI) doCreateNodeProperty(schemaName string, prop *structs.Property, conf *configuration.RequestConfig) (resp *http.UQLResponse, err error) {
@@ -136,16 +128,12 @@ func (api *UltipaAPI) doCreateNodeProperty(schemaName string, prop *structs.Prop
 	if err != nil {
 		return nil, err
 	}
-	propName := prop.Name
-	if utils.IsNeedToEscapeName(propName) {
-		propName = fmt.Sprintf("`%v`", propName)
-	} else {
-		propName = fmt.Sprintf(`"%v"`, propName)
-	}
-
-	uql := fmt.Sprintf(`create().node_property(@%v,%s,"%v","%v")`, schemaName, propName, propertyTypeStr, prop.Desc)
-
-	resp, err = api.UQL(uql, conf)
+	resp, err = api.UQLWithParams(`create().node_property(@$schema, $name, $type, $description)`, map[string]interface{}{
+		"schema":      utils.UqlName(schemaName),
+		"name":        utils.UqlQuotedName(prop.Name),
+		"type":        propertyTypeStr,
+		"description": prop.Desc,
+	}, conf)
 	return resp, err
 }
 
@@ -164,12 +152,7 @@ func (api *UltipaAPI) CreateEdgeProperty(schemaName string, prop *structs.Proper
 		return nil, errors.New(fmt.Sprintf("%s, propertyName = %s", err.Error(), prop.Name))
 	}
 
-	escapeSchemaName := schemaName
-	if utils.IsNeedToEscapeSchemaNameForProperty(schemaName) {
-		escapeSchemaName = fmt.Sprintf("`%v`", schemaName)
-	}
-
-	return api.doCreateEdgeProperty(escapeSchemaName, prop, conf)
+	return api.doCreateEdgeProperty(schemaName, prop, conf)
 }
 
 func (api *UltipaAPI) doCreateEdgeProperty(schemaName string, prop *structs.Property, conf *configuration.RequestConfig) (resp *http.UQLResponse, err error) {
@@ -182,44 +165,98 @@ func (api *UltipaAPI) doCreateEdgeProperty(schemaName string, prop *structs.Prop
 		return nil, err
 	}
 
-	propName := prop.Name
-	if utils.IsNeedToEscapeName(propName) {
-		propName = fmt.Sprintf("`%s`", propName)
-	} else {
-		propName = fmt.Sprintf(`"%s"`, propName)
-	}
-
-	uql := fmt.Sprintf(`create().edge_property(@%v,%v,"%v","%v")`, schemaName, propName, propertyTypeStr, prop.Desc)
-	resp, err = api.UQL(uql, conf)
+	resp, err = api.UQLWithParams(`create().edge_property(@$schema, $name, $type, $description)`, map[string]interface{}{
+		"schema":      utils.UqlName(schemaName),
+		"name":        utils.UqlQuotedName(prop.Name),
+		"type":        propertyTypeStr,
+		"description": prop.Desc,
+	}, conf)
 	return resp, err
 }
 
 // Usage: AlterNodeProperty("@schemaName.propertyName", dbType *ultipa.DBType, &*structs.Property{Name, Desc}, *RequestConfig)
 func (api *UltipaAPI) AlterNodeProperty(propertyName string, prop *structs.Property, config *configuration.RequestConfig) (resp *http.UQLResponse, err error) {
-
-	resp, err = api.UQL(fmt.Sprintf(`alter().node_property(%v).set({name: "%v", description: "%v"})`, propertyName, prop.Name, prop.Desc), config)
-
-	return resp, err
+	return api.alterProperty("node_property", propertyName, prop, config)
 }
 
 // Usage: AlterEdgeProperty("@schemaName.propertyName", dbType *ultipa.DBType, &*structs.Property{Name, Desc}, *RequestConfig)
 func (api *UltipaAPI) AlterEdgeProperty(propertyName string, prop *structs.Property, conf *configuration.RequestConfig) (resp *http.UQLResponse, err error) {
-
-	resp, err = api.UQL(fmt.Sprintf(`alter().edge_property(%v).set({name: "%v", description: "%v"})`, propertyName, prop.Name, prop.Desc), conf)
-
-	return resp, err
+	return api.alterProperty("edge_property", propertyName, prop, conf)
 }
 
 // Usage: DropNodeProperty("@schemaName.propertyName", *RequestConfig)
 func (api *UltipaAPI) DropNodeProperty(propertyName string, config *configuration.RequestConfig) (resp *http.UQLResponse, err error) {
-	resp, err = api.UQL(fmt.Sprintf(`drop().node_property(%v)`, propertyName), config)
-
-	return resp, err
+	return api.dropProperty("node_property", propertyName, config)
 }
 
 // Usage: DropEdgeProperty("@schemaName.propertyName", *RequestConfig)
 func (api *UltipaAPI) DropEdgeProperty(propertyName string, config *configuration.RequestConfig) (resp *http.UQLResponse, err error) {
-	resp, err = api.UQL(fmt.Sprintf(`drop().edge_property(%v)`, propertyName), config)
+	return api.dropProperty("edge_property", propertyName, config)
+}
 
-	return resp, err
+func (api *UltipaAPI) alterProperty(target string, propertyName string, prop *structs.Property, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	params, err := schemaPropertyParams(propertyName)
+	if err != nil {
+		return nil, err
+	}
+	params["name"] = prop.Name
+	params["description"] = prop.Desc
+	return api.UQLWithParams("alter()."+target+"(@$schema.$property).set({name: $name, description: $description})", params, config)
+}
+
+func (api *UltipaAPI) dropProperty(target string, propertyName string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	params, err := schemaPropertyParams(propertyName)
+	if err != nil {
+		return nil, err
+	}
+	return api.UQLWithParams("drop()."+target+"(@$schema.$property)", params, config)
+}
+
+// schemaPropertyParams binds the parts of @schema.property as escaped names, * is kept for all schemas
+func schemaPropertyParams(propertyName string) (map[string]interface{}, error) {
+	schema, property, err := splitSchemaProperty(propertyName)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{"schema": utils.UqlName(schema), "property": utils.UqlName(property)}
+	if schema == "*" {
+		params["schema"] = utils.UqlRaw("*")
+	}
+	return params, nil
+}
+
+// splitSchemaProperty splits @schema.property into schema and property, names escaped by backticks are unescaped
+func splitSchemaProperty(propertyName string) (string, string, error) {
+	invalid := errors.New(fmt.Sprintf("property should be @schema.property, propertyName = %s", propertyName))
+	value := strings.TrimSpace(propertyName)
+	if !strings.HasPrefix(value, "@") {
+		return "", "", invalid
+	}
+	value = value[1:]
+
+	schema := ""
+	if strings.HasPrefix(value, "`") {
+		end := strings.Index(value[1:], "`")
+		if end < 0 {
+			return "", "", invalid
+		}
+		schema, value = value[1:end+1], value[end+2:]
+	} else {
+		dot := strings.Index(value, ".")
+		if dot < 0 {
+			return "", "", invalid
+		}
+		schema, value = value[:dot], value[dot:]
+	}
+	if !strings.HasPrefix(value, ".") {
+		return "", "", invalid
+	}
+	property := value[1:]
+	if strings.HasPrefix(property, "`") && strings.HasSuffix(property, "`") && len(property) > 1 {
+		property = property[1 : len(property)-1]
+	}
+	if schema == "" || property == "" {
+		return "", "", invalid
+	}
+	return schema, property, nil
 }
@@ -100,11 +100,9 @@ func (api *UltipaAPI) GetNodeSchema(schemaName string, config *configuration.Req
 	}
 	var resp *http.UQLResponse
 	var schemas []*structs.Schema
-	escapedSchemaName := schemaName
-	if utils.IsNeedToEscapeName(schemaName) {
-		escapedSchemaName = fmt.Sprintf("`%v`", schemaName)
-	}
-	resp, err = api.UQL(fmt.Sprintf(`show().node_schema(@%v)`, escapedSchemaName), config)
+	resp, err = api.UQLWithParams(`show().node_schema(@$schema)`, map[string]interface{}{
+		"schema": utils.UqlName(schemaName),
+	}, config)
 	if err != nil {
 		return nil, err
 	}
@@ -125,11 +123,9 @@ func (api *UltipaAPI) GetEdgeSchema(schemaName string, config *configuration.Req
 	}
 	var resp *http.UQLResponse
 	var schemas []*structs.Schema
-	escapedSchemaName := schemaName
-	if utils.IsNeedToEscapeName(schemaName) {
-		escapedSchemaName = fmt.Sprintf("`%v`", schemaName)
-	}
-	resp, err = api.UQL(fmt.Sprintf(`show().edge_schema(@%v)`, escapedSchemaName), config)
+	resp, err = api.UQLWithParams(`show().edge_schema(@$schema)`, map[string]interface{}{
+		"schema": utils.UqlName(schemaName),
+	}, config)
 	if err != nil {
 		return nil, err
 	}
@@ -150,18 +146,14 @@ func (api *UltipaAPI) CreateSchema(schema *structs.Schema, isCreateProperties bo
 	}
 	var resp *http.UQLResponse
 
-	schemaName := schema.Name
-	if utils.IsNeedToEscapeName(schemaName) {
-		schemaName = fmt.Sprintf("`%v`", schemaName)
-	} else {
-		schemaName = fmt.Sprintf(`"%v"`, schemaName)
+	params := map[string]interface{}{
+		"name":        utils.UqlQuotedName(schema.Name),
+		"description": schema.Desc,
 	}
 	api.Logger.Log("Creating Schema : @" + schema.Name)
 
 	if schema.DBType == ultipa.DBType_DBNODE {
-		uql := fmt.Sprintf(`create().node_schema(%v,"%v")`, schemaName, schema.Desc)
-
-		resp, err = api.UQL(uql, conf)
+		resp, err = api.UQLWithParams(`create().node_schema($name, $description)`, params, conf)
 		if err != nil {
 			return nil, err
 		}
@@ -170,8 +162,7 @@ func (api *UltipaAPI) CreateSchema(schema *structs.Schema, isCreateProperties bo
 		}
 
 	} else if schema.DBType == ultipa.DBType_DBEDGE {
-		uql := fmt.Sprintf(`create().edge_schema(%v,"%v")`, schemaName, schema.Desc)
-		resp, err = api.UQL(uql, conf)
+		resp, err = api.UQLWithParams(`create().edge_schema($name, $description)`, params, conf)
 		if err != nil {
 			return nil, err
 		}
@@ -227,3 +218,153 @@ func (api *UltipaAPI) CreateSchemaIfNotExist(schema *structs.Schema, config *con
 	return exist, err
 
 }
+
+// requestGraph returns the graph that a request of config is sent to
+func (api *UltipaAPI) requestGraph(config *configuration.RequestConfig) string {
+	if config != nil && config.GraphName != "" {
+		return config.GraphName
+	}
+	if api.Config != nil {
+		return api.Config.CurrentGraph
+	}
+	return ""
+}
+
+// getExistingSchema returns the schema, or SchemaNotFoundError if it does not exist
+func (api *UltipaAPI) getExistingSchema(schemaName string, dbType ultipa.DBType, config *configuration.RequestConfig) (*structs.Schema, error) {
+	schema, err := api.GetSchema(schemaName, dbType, config)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, utils.NewSchemaNotFoundError(api.requestGraph(config), structs.DBTypeToString(dbType), schemaName)
+	}
+	return schema, nil
+}
+
+// AlterSchema renames the schema or changes its description, empty fields of schema are not changed
+func (api *UltipaAPI) AlterSchema(dbType ultipa.DBType, schemaName string, schema *structs.Schema, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	command, err := dbTypeCommand(dbType, "schema")
+	if err != nil {
+		return nil, err
+	}
+	graph := api.requestGraph(config)
+	values := map[string]interface{}{}
+	if schema.Name != "" && schema.Name != schemaName {
+		if err = CheckName(schema.Name); err != nil {
+			return nil, errors.New(fmt.Sprintf("%s, schemaName = %s", err.Error(), schema.Name))
+		}
+		existing, err := api.GetSchema(schema.Name, dbType, config)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, utils.NewSchemaExistsError(graph, structs.DBTypeToString(dbType), schema.Name)
+		}
+		values["name"] = schema.Name
+	}
+	if schema.Desc != "" {
+		values["description"] = schema.Desc
+	}
+	if len(values) == 0 {
+		return nil, errors.New("nothing to alter for schema @" + schemaName)
+	}
+	if _, err = api.getExistingSchema(schemaName, dbType, config); err != nil {
+		return nil, err
+	}
+
+	resp, err := api.UQLWithParams("alter()."+command+"(@$schema).set($values)", map[string]interface{}{
+		"schema": utils.UqlName(schemaName),
+		"values": values,
+	}, config)
+	if err != nil {
+		return nil, err
+	}
+	api.invalidateGraphCache(graph)
+	if !resp.Status.IsSuccess() {
+		return resp, utils.NewSchemaOperationError("alter", graph, structs.DBTypeToString(dbType), schemaName, resp.Status.Message)
+	}
+	return resp, nil
+}
+
+// DropSchema drops the schema with its nodes or edges
+func (api *UltipaAPI) DropSchema(dbType ultipa.DBType, schemaName string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	command, err := dbTypeCommand(dbType, "schema")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = api.getExistingSchema(schemaName, dbType, config); err != nil {
+		return nil, err
+	}
+
+	graph := api.requestGraph(config)
+	resp, err := api.UQLWithParams("drop()."+command+"(@$schema)", map[string]interface{}{
+		"schema": utils.UqlName(schemaName),
+	}, config)
+	if err != nil {
+		return nil, err
+	}
+	api.invalidateGraphCache(graph)
+	if !resp.Status.IsSuccess() {
+		return resp, utils.NewSchemaOperationError("drop", graph, structs.DBTypeToString(dbType), schemaName, resp.Status.Message)
+	}
+	return resp, nil
+}
+
+// CopySchemaDefinition creates the schema and its properties of fromGraph in toGraph, nodes and edges are not copied.
+// If the schema exists in toGraph, the missing properties are created, and properties of different types are reported as SchemaOperationError
+func (api *UltipaAPI) CopySchemaDefinition(dbType ultipa.DBType, schemaName string, fromGraph string, toGraph string, config *configuration.RequestConfig) (*structs.Schema, error) {
+	fromConfig := &configuration.RequestConfig{}
+	if config != nil {
+		copied := *config
+		fromConfig = &copied
+	}
+	toConfig := *fromConfig
+	fromConfig.GraphName = fromGraph
+	toConfig.GraphName = toGraph
+
+	schema, err := api.getExistingSchema(schemaName, dbType, fromConfig)
+	if err != nil {
+		return nil, err
+	}
+	schema.DBType = dbType
+
+	existing, err := api.GetSchema(schemaName, dbType, &toConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer api.invalidateGraphCache(toGraph)
+
+	if existing == nil {
+		resp, err := api.CreateSchema(schema, true, &toConfig)
+		if err != nil {
+			return nil, utils.NewSchemaOperationError("copy", toGraph, structs.DBTypeToString(dbType), schemaName, err.Error())
+		}
+		if resp != nil && !resp.Status.IsSuccess() {
+			return nil, utils.NewSchemaOperationError("copy", toGraph, structs.DBTypeToString(dbType), schemaName, resp.Status.Message)
+		}
+		return schema, nil
+	}
+
+	for _, prop := range schema.Properties {
+		if prop.IsIDType() || prop.IsIgnore() {
+			continue
+		}
+		existingProp := existing.GetProperty(prop.Name)
+		if existingProp == nil {
+			resp, err := api.CreateProperty(schemaName, dbType, prop, &toConfig)
+			if err == nil && !resp.Status.IsSuccess() {
+				err = errors.New(resp.Status.Message)
+			}
+			if err != nil {
+				return nil, utils.NewSchemaOperationError("copy", toGraph, structs.DBTypeToString(dbType), schemaName, fmt.Sprintf("failed to create property %s: %v", prop.Name, err))
+			}
+			continue
+		}
+		if existingProp.Type != prop.Type {
+			return nil, utils.NewSchemaOperationError("copy", toGraph, structs.DBTypeToString(dbType), schemaName,
+				fmt.Sprintf("property %s is %v in graph %s but %v in graph %s", prop.Name, existingProp.Type, toGraph, prop.Type, fromGraph))
+		}
+	}
+	return schema, nil
+}
@@ -0,0 +1,268 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/connection"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils/logger"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// SlowQueryRecord is the profile of a uql request
+type SlowQueryRecord struct {
+	Time       time.Time          `json:"time"`
+	Uql        string             `json:"uql"`
+	Graph      string             `json:"graph"`
+	Host       string             `json:"host"`
+	Role       string             `json:"role"`
+	Latency    time.Duration      `json:"latency"`     // client observed latency, nanoseconds in json
+	TotalCost  int                `json:"total_cost"`  // milliseconds, from Statistic.TotalCost
+	EngineCost int                `json:"engine_cost"` // milliseconds, from Statistic.EngineCost
+	Rows       map[string]int     `json:"rows"`        // row counts by alias
+	Explain    []*structs.Explain `json:"explain,omitempty"`
+	Code       string             `json:"code,omitempty"`
+	Error      string             `json:"error,omitempty"`
+}
+
+// SlowQuerySink receives the records of slow queries
+type SlowQuerySink interface {
+	Record(record *SlowQueryRecord) error
+}
+
+// SlowQueryFunc is a SlowQuerySink calling the func for each record
+type SlowQueryFunc func(record *SlowQueryRecord)
+
+func (f SlowQueryFunc) Record(record *SlowQueryRecord) error {
+	f(record)
+	return nil
+}
+
+// SlowQueryLoggerSink writes records as json into the sdk logger, it is the sink when SlowQueryLogFile is not set
+type SlowQueryLoggerSink struct{}
+
+func (SlowQueryLoggerSink) Record(record *SlowQueryRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	logger.PrintWarn("slow query: " + string(line))
+	return nil
+}
+
+// SlowQueryLog records the uql whose latency or total cost reaches Threshold into Sink
+type SlowQueryLog struct {
+	Threshold time.Duration // 0 means recording every uql
+	Sink      SlowQuerySink
+}
+
+func NewSlowQueryLog(threshold time.Duration, sink SlowQuerySink) *SlowQueryLog {
+	return &SlowQueryLog{
+		Threshold: threshold,
+		Sink:      sink,
+	}
+}
+
+// IsSlow checks whether a request taking latency, with totalCost milliseconds in server, reaches the threshold
+func (l *SlowQueryLog) IsSlow(latency time.Duration, totalCost int) bool {
+	return latency >= l.Threshold || time.Duration(totalCost)*time.Millisecond >= l.Threshold
+}
+
+// Log sends the record to sink if it is slow, or force is true
+func (l *SlowQueryLog) Log(record *SlowQueryRecord, force bool) error {
+	if l.Sink == nil {
+		return nil
+	}
+	if !force && !l.IsSlow(record.Latency, record.TotalCost) {
+		return nil
+	}
+	return l.Sink.Record(record)
+}
+
+// Close closes Sink if it is an io.Closer, e.g. SlowQueryFileSink, it is called when the client is closed
+func (l *SlowQueryLog) Close() error {
+	if closer, ok := l.Sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// SlowQueryFileSink writes records as json lines into a file,
+// when the file reaches MaxSize bytes, it is renamed to path.1, path.1 to path.2 ..., and at most MaxBackups files are kept
+type SlowQueryFileSink struct {
+	Path       string
+	MaxSize    int64 // 0 means never rotating
+	MaxBackups int
+
+	lock sync.Mutex
+	file *os.File
+	size int64
+}
+
+func NewSlowQueryFileSink(path string, maxSize int64, maxBackups int) (*SlowQueryFileSink, error) {
+	sink := &SlowQueryFileSink{
+		Path:       path,
+		MaxSize:    maxSize,
+		MaxBackups: maxBackups,
+	}
+	err := sink.open()
+	if err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (s *SlowQueryFileSink) open() error {
+	file, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.New(fmt.Sprintf("failed to open slow query log %s: %v", s.Path, err))
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+func (s *SlowQueryFileSink) Record(record *SlowQueryRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.file == nil {
+		return errors.New("slow query log is closed: " + s.Path)
+	}
+	if s.MaxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.MaxSize {
+		err = s.rotate()
+		if err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+func (s *SlowQueryFileSink) rotate() error {
+	err := s.file.Close()
+	s.file = nil
+	if err != nil {
+		return err
+	}
+	if s.MaxBackups <= 0 {
+		err = os.Remove(s.Path)
+	} else {
+		os.Remove(fmt.Sprintf("%s.%d", s.Path, s.MaxBackups))
+		for i := s.MaxBackups - 1; i > 0; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", s.Path, i), fmt.Sprintf("%s.%d", s.Path, i+1))
+		}
+		err = os.Rename(s.Path, s.Path+".1")
+	}
+	if err != nil {
+		return err
+	}
+	return s.open()
+}
+
+// Close closes the log file, records after closing return an error
+func (s *SlowQueryFileSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// NewSlowQueryRecord builds the record of uql from its response, resp and conn may be nil if the request failed
+func NewSlowQueryRecord(uql string, graph string, conn *connection.Connection, start time.Time, resp *http.UQLResponse, err error) *SlowQueryRecord {
+	record := &SlowQueryRecord{
+		Time:    start,
+		Uql:     uql,
+		Graph:   graph,
+		Latency: time.Since(start),
+		Rows:    map[string]int{},
+	}
+	if conn != nil {
+		record.Host = conn.Host
+		record.Role = fmt.Sprintf("%v", conn.Role)
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if resp == nil {
+		return record
+	}
+	if resp.Status != nil {
+		record.Code = resp.Status.Code.String()
+		if resp.Status.Code != ultipa.ErrorCode_SUCCESS {
+			record.Error = resp.Status.Message
+		}
+	}
+	if resp.Statistic != nil {
+		record.TotalCost = resp.Statistic.TotalCost
+		record.EngineCost = resp.Statistic.EngineCost
+	}
+	if resp.ExplainPlan != nil && len(resp.ExplainPlan.Explain) > 0 {
+		record.Explain = resp.ExplainPlan.Explain
+	}
+	record.Rows = resp.RowCounts()
+	return record
+}
+
+// logSlowQuery records the uql request if the slow query log is enabled, failures of sink are printed as warnings
+func (api *UltipaAPI) logSlowQuery(uql string, config *configuration.RequestConfig, conf *configuration.UltipaConfig, conn *connection.Connection, start time.Time, resp *http.UQLResponse, err error) {
+	if api.SlowQueryLog == nil {
+		return
+	}
+	api.recordSlowQuery(NewSlowQueryRecord(uql, api.slowQueryGraph(conf), conn, start, resp, err), config)
+}
+
+// logSlowStream returns the OnClose callback of UQLStream and Query, which records the request when the stream is drained or closed,
+// so the latency includes the time to consume the stream
+func (api *UltipaAPI) logSlowStream(uql string, config *configuration.RequestConfig, conf *configuration.UltipaConfig, conn *connection.Connection, start time.Time) func(summary *http.StreamSummary) {
+	return func(summary *http.StreamSummary) {
+		if api.SlowQueryLog == nil {
+			return
+		}
+		resp := &http.UQLResponse{
+			Status:      summary.Status,
+			Statistic:   summary.Statistic,
+			ExplainPlan: summary.ExplainPlan,
+		}
+		record := NewSlowQueryRecord(uql, api.slowQueryGraph(conf), conn, start, resp, summary.Err)
+		record.Rows = summary.RowCounts
+		api.recordSlowQuery(record, config)
+	}
+}
+
+func (api *UltipaAPI) slowQueryGraph(conf *configuration.UltipaConfig) string {
+	if conf != nil {
+		return conf.CurrentGraph
+	}
+	return api.Config.CurrentGraph
+}
+
+func (api *UltipaAPI) recordSlowQuery(record *SlowQueryRecord, config *configuration.RequestConfig) {
+	force := config != nil && config.Profile
+	if logErr := api.SlowQueryLog.Log(record, force); logErr != nil {
+		logger.PrintWarn(fmt.Sprintf("failed to record slow query: %v", logErr))
+	}
+}
@@ -0,0 +1,290 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// TaskManager submits algorithm tasks by exec task, and manages them by id
+type TaskManager struct {
+	API          *UltipaAPI
+	PollInterval time.Duration // interval of polling in Task.Wait
+}
+
+// Task is a handle of an algorithm task, the fields of structs.Task are refreshed by Refresh and Wait
+type Task struct {
+	*structs.Task
+	manager *TaskManager
+	config  *configuration.RequestConfig
+}
+
+var DefaultTaskPollInterval = time.Second
+
+func NewTaskManager(api *UltipaAPI) *TaskManager {
+	return &TaskManager{
+		API:          api,
+		PollInterval: DefaultTaskPollInterval,
+	}
+}
+
+// TaskManager returns a TaskManager of the client
+func (api *UltipaAPI) TaskManager() *TaskManager {
+	return NewTaskManager(api)
+}
+
+// copyTaskConfig keeps the graph and timeout of config, so later requests of the task go to the same graph
+func copyTaskConfig(config *configuration.RequestConfig) *configuration.RequestConfig {
+	if config == nil {
+		return &configuration.RequestConfig{}
+	}
+	return &configuration.RequestConfig{
+		GraphName: config.GraphName,
+		Timeout:   config.Timeout,
+		ClusterId: config.ClusterId,
+		Host:      config.Host,
+	}
+}
+
+func parseTaskId(taskId string) (uint64, error) {
+	id, err := strconv.ParseUint(taskId, 10, 64)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("invalid task id %q", taskId))
+	}
+	return id, nil
+}
+
+// Submit sends the algo uql as exec task, and returns the created task
+// Usage: Submit(`algo(degree).params({order: "desc"}).write({file:{filename: "degree"}})`, config)
+func (m *TaskManager) Submit(algoUql string, config *configuration.RequestConfig) (*Task, error) {
+	config = copyTaskConfig(config)
+
+	uql := algoUql
+	if !utils.ClassifyUql(algoUql).ExecTask {
+		uql = "exec task " + algoUql
+	}
+
+	resp, err := m.API.UQL(uql, config)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, errors.New(fmt.Sprintf("failed to submit task: %s", resp.Status.Message))
+	}
+
+	taskId, err := taskIdFromResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	return m.Get(taskId, config)
+}
+
+// taskIdFromResponse finds the task id from the response of exec task, which is a _task table or a task_id column
+func taskIdFromResponse(resp *http.UQLResponse) (string, error) {
+	for _, alias := range resp.AliasList {
+		table, err := resp.Alias(alias).AsTable()
+		if err != nil || table == nil || len(table.Rows) == 0 {
+			continue
+		}
+		if alias == http.RESP_TASK_KEY {
+			tasks, err := resp.Alias(alias).AsTasks()
+			if err == nil && len(tasks) > 0 && tasks[0].Id != "" {
+				return tasks[0].Id, nil
+			}
+		}
+		for _, kv := range table.ToKV() {
+			if id := kv.Get("task_id"); id != nil {
+				return fmt.Sprint(id), nil
+			}
+		}
+	}
+	return "", errors.New("task id is not found in the response of exec task")
+}
+
+// Get shows the task by id
+func (m *TaskManager) Get(taskId string, config *configuration.RequestConfig) (*Task, error) {
+	id, err := parseTaskId(taskId)
+	if err != nil {
+		return nil, err
+	}
+	config = copyTaskConfig(config)
+
+	tasks, err := m.list("show().task($id)", map[string]interface{}{"id": id}, config)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		if task.Id == taskId {
+			return task, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("task %s is not found", taskId))
+}
+
+// List shows the tasks, filtered by algo name and status if they are not empty, e.g. List("degree", "done", nil)
+func (m *TaskManager) List(algo string, status string, config *configuration.RequestConfig) ([]*Task, error) {
+	config = copyTaskConfig(config)
+
+	if algo == "" && status == "" {
+		return m.list("show().task()", nil, config)
+	}
+	params := map[string]interface{}{"algo": "*", "status": "*"}
+	if algo != "" {
+		params["algo"] = algo
+	}
+	if status != "" {
+		params["status"] = status
+	}
+	return m.list("show().task($algo, $status)", params, config)
+}
+
+func (m *TaskManager) list(uql string, params map[string]interface{}, config *configuration.RequestConfig) ([]*Task, error) {
+	resp, err := m.API.UQLWithParams(uql, params, config)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, errors.New(resp.Status.Message)
+	}
+
+	taskItems, err := resp.Alias(http.RESP_TASK_KEY).AsTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*Task
+	for _, item := range taskItems {
+		tasks = append(tasks, &Task{
+			Task:    item,
+			manager: m,
+			config:  config,
+		})
+	}
+	return tasks, nil
+}
+
+// operate sends command().task(id), e.g. stop().task(1)
+func (m *TaskManager) operate(command string, taskId string, config *configuration.RequestConfig) error {
+	id, err := parseTaskId(taskId)
+	if err != nil {
+		return err
+	}
+	resp, err := m.API.UQLWithParams(command+"().task($id)", map[string]interface{}{
+		"id": id,
+	}, copyTaskConfig(config))
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return errors.New(fmt.Sprintf("failed to %s task %s: %s", command, taskId, resp.Status.Message))
+	}
+	return nil
+}
+
+func (m *TaskManager) Stop(taskId string, config *configuration.RequestConfig) error {
+	return m.operate("stop", taskId, config)
+}
+
+func (m *TaskManager) Pause(taskId string, config *configuration.RequestConfig) error {
+	return m.operate("pause", taskId, config)
+}
+
+func (m *TaskManager) Resume(taskId string, config *configuration.RequestConfig) error {
+	return m.operate("resume", taskId, config)
+}
+
+// Clear removes the task and its result files from server
+func (m *TaskManager) Clear(taskId string, config *configuration.RequestConfig) error {
+	return m.operate("clear", taskId, config)
+}
+
+// Refresh shows the task again and updates its fields
+func (t *Task) Refresh() error {
+	task, err := t.manager.Get(t.Id, t.config)
+	if err != nil {
+		return err
+	}
+	t.Task = task.Task
+	return nil
+}
+
+// Wait polls the task until it is finished, returns an error if the task failed or stopped, or ctx is done
+func (t *Task) Wait(ctx context.Context) error {
+	interval := t.manager.PollInterval
+	if interval <= 0 {
+		interval = DefaultTaskPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if t.IsFinished() {
+			if !t.IsDone() {
+				return errors.New(fmt.Sprintf("task %s %s is %s: %v", t.Id, t.Algo, t.StatusName(), t.Result))
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if err := t.Refresh(); err != nil {
+			return err
+		}
+	}
+}
+
+func (t *Task) Stop() error {
+	return t.manager.Stop(t.Id, t.config)
+}
+
+func (t *Task) Pause() error {
+	return t.manager.Pause(t.Id, t.config)
+}
+
+func (t *Task) Resume() error {
+	return t.manager.Resume(t.Id, t.config)
+}
+
+func (t *Task) Clear() error {
+	return t.manager.Clear(t.Id, t.config)
+}
+
+// Download receives a result file of the task by DownloadFileV2
+func (t *Task) Download(fileName string, receive func(data []byte) error) error {
+	return t.manager.API.DownloadFileV2(fileName, t.Id, copyTaskConfig(t.config), receive)
+}
+
+// DownloadAll saves all the ResultFiles into dir, and returns the saved paths
+func (t *Task) DownloadAll(dir string) ([]string, error) {
+	var paths []string
+	for _, fileName := range t.ResultFiles {
+		filePath := filepath.Join(dir, filepath.Base(fileName))
+		file, err := os.Create(filePath)
+		if err != nil {
+			return paths, err
+		}
+		err = t.Download(fileName, func(data []byte) error {
+			_, err := file.Write(data)
+			return err
+		})
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, filePath)
+	}
+	return paths, nil
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils/logger"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var DefaultUploadChunkSize = 1024 * 1024 // 1MB
+
+var DefaultUploadRetries = 3
+
+// GraphFile a local node or edge file to be uploaded by UploadGraphFiles
+type GraphFile struct {
+	DBType ultipa.DBType
+	Path   string
+	Name   string // file name in server, default is the base name of Path
+}
+
+func (f *GraphFile) fileName() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return filepath.Base(f.Path)
+}
+
+// UploadProgress progress of UploadGraphFiles, reported after each chunk is sent
+type UploadProgress struct {
+	File       *GraphFile
+	FileIndex  int // index of File in files
+	TotalFiles int
+	FileSent   uint64 // bytes of File sent
+	FileSize   uint64
+	TotalSent  uint64 // bytes of all files sent, including the files uploaded before
+	TotalSize  uint64
+	Attempt    int // 1 for the first attempt of File, increased by retries
+}
+
+// UploadGraphOptions options of UploadGraphFiles
+type UploadGraphOptions struct {
+	GraphDescription string
+	ChunkSize        int                            // default is DefaultUploadChunkSize
+	MaxRetries       int                            // retries of a file failed to upload, default is DefaultUploadRetries, negative disables retries
+	RetryInterval    time.Duration                  // wait between retries, default is 1s
+	Uploaded         []string                       // names of files uploaded by a previous call, they are skipped to resume the upload
+	OnProgress       func(progress *UploadProgress) // called after each chunk is sent
+	Config           *configuration.RequestConfig
+}
+
+// UploadGraphResult result of UploadGraphFiles, Uploaded can be passed to UploadGraphOptions.Uploaded to resume a failed upload
+type UploadGraphResult struct {
+	Graph    string
+	Uploaded []string // names of files uploaded, including the skipped ones
+	Retries  int
+	Created  bool // CreateGraphByUploader succeeded
+}
+
+// UploadGraphFiles streams node and edge files to the server by the Uploader rpc in chunks, then creates the graph of them by CreateGraphByUploader.
+// A file failed to upload is sent again from the beginning up to MaxRetries times, the files uploaded are not sent again.
+// Usage: UploadGraphFiles(ctx, "amz", []*GraphFile{{DBType: ultipa.DBType_DBNODE, Path: "./account.csv"}}, nil)
+func (api *UltipaAPI) UploadGraphFiles(ctx context.Context, graphName string, files []*GraphFile, opts *UploadGraphOptions) (*UploadGraphResult, error) {
+	if opts == nil {
+		opts = &UploadGraphOptions{}
+	}
+	if graphName == "" {
+		return nil, errors.New("graph name can not be empty")
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no file to upload")
+	}
+	if err := api.requireFeature(FeatureUploader); err != nil {
+		return nil, err
+	}
+
+	config := &configuration.RequestConfig{}
+	if opts.Config != nil {
+		copied := *opts.Config
+		config = &copied
+	}
+	if ctx != nil {
+		config.Context = ctx
+	}
+	for _, operation := range []Operation{OperationUploader, OperationCreateGraphByUploader} {
+		if err := api.preflight(operation, "", config); err != nil {
+			return nil, err
+		}
+	}
+
+	uploaded := map[string]bool{}
+	for _, name := range opts.Uploaded {
+		uploaded[name] = true
+	}
+
+	var totalSize uint64
+	sizes := make([]uint64, len(files))
+	for i, file := range files {
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			return nil, errors.New(fmt.Sprintf("%s is a directory", file.Path))
+		}
+		sizes[i] = uint64(info.Size())
+		totalSize += sizes[i]
+	}
+
+	result := &UploadGraphResult{Graph: graphName}
+	progress := &UploadProgress{TotalFiles: len(files), TotalSize: totalSize}
+
+	for i, file := range files {
+		name := file.fileName()
+		if uploaded[name] {
+			progress.TotalSent += sizes[i]
+			result.Uploaded = append(result.Uploaded, name)
+			continue
+		}
+
+		sentBefore := progress.TotalSent
+		var err error
+		for attempt := 1; ; attempt++ {
+			progress.File = file
+			progress.FileIndex = i
+			progress.FileSize = sizes[i]
+			progress.FileSent = 0
+			progress.TotalSent = sentBefore
+			progress.Attempt = attempt
+
+			err = api.uploadGraphFile(graphName, file, progress, opts, config)
+			if err == nil || !isRetryableUploadError(err) || attempt > uploadRetries(opts) {
+				break
+			}
+			result.Retries++
+			logger.PrintWarn(fmt.Sprintf("failed to upload %s, retry %d: %v", name, attempt, err))
+			if err = waitUploadRetry(config.Context, opts); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			return result, errors.New(fmt.Sprintf("failed to upload %s of graph %s: %v", name, graphName, err))
+		}
+		result.Uploaded = append(result.Uploaded, name)
+	}
+
+	if err := api.createGraphByUploader(graphName, opts.GraphDescription, config); err != nil {
+		return result, err
+	}
+	result.Created = true
+	return result, nil
+}
+
+// uploadFatalError errors not fixed by retrying, e.g. a failed status replied by server or a local file error
+type uploadFatalError struct {
+	message string
+}
+
+func (e *uploadFatalError) Error() string {
+	return e.message
+}
+
+func isRetryableUploadError(err error) bool {
+	var statusErr *uploadFatalError
+	return !errors.As(err, &statusErr) && !errors.Is(err, context.Canceled)
+}
+
+func uploadRetries(opts *UploadGraphOptions) int {
+	if opts.MaxRetries == 0 {
+		return DefaultUploadRetries
+	}
+	if opts.MaxRetries < 0 {
+		return 0
+	}
+	return opts.MaxRetries
+}
+
+func waitUploadRetry(ctx context.Context, opts *UploadGraphOptions) error {
+	interval := opts.RetryInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if ctx == nil {
+		time.Sleep(interval)
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(interval):
+		return nil
+	}
+}
+
+// uploadGraphFile sends a file by an Uploader stream
+func (api *UltipaAPI) uploadGraphFile(graphName string, file *GraphFile, progress *UploadProgress, opts *UploadGraphOptions, config *configuration.RequestConfig) error {
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return &uploadFatalError{message: err.Error()}
+	}
+	defer f.Close()
+
+	client, err := api.GetControlClient(config)
+	if err != nil {
+		return err
+	}
+	ctx, cancel, err := api.Pool.NewContext(config)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	stream, err := client.Uploader(ctx)
+	if err != nil {
+		return err
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultUploadChunkSize
+	}
+	chunk := make([]byte, chunkSize)
+	for {
+		n, err := f.Read(chunk)
+		if n > 0 {
+			err := stream.Send(&ultipa.UploaderRequest{
+				DbType:          file.DBType,
+				GraphName:       graphName,
+				TotalFileCounts: uint64(progress.TotalFiles),
+				FileName:        file.fileName(),
+				FileSize:        progress.FileSize,
+				Chunk:           chunk[:n],
+			})
+			if err != nil {
+				// the error of the stream is returned by CloseAndRecv
+				if _, recvErr := stream.CloseAndRecv(); recvErr != nil {
+					return recvErr
+				}
+				return err
+			}
+			progress.FileSent += uint64(n)
+			progress.TotalSent += uint64(n)
+			if opts.OnProgress != nil {
+				opts.OnProgress(progress)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return &uploadFatalError{message: err.Error()}
+		}
+	}
+
+	reply, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	if reply.Status != nil && reply.Status.ErrorCode != ultipa.ErrorCode_SUCCESS {
+		return &uploadFatalError{message: reply.Status.Msg}
+	}
+	return nil
+}
+
+func (api *UltipaAPI) createGraphByUploader(graphName string, description string, config *configuration.RequestConfig) error {
+	client, err := api.GetControlClient(config)
+	if err != nil {
+		return err
+	}
+	ctx, cancel, err := api.Pool.NewContext(config)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	reply, err := client.CreateGraphByUploader(ctx, &ultipa.CreateGraphByUploaderRequest{
+		GraphName:        graphName,
+		GraphDescription: description,
+	})
+	if err != nil {
+		return err
+	}
+	if reply.Status != nil && reply.Status.ErrorCode != ultipa.ErrorCode_SUCCESS {
+		return errors.New(fmt.Sprintf("failed to create graph %s by uploader: %s", graphName, reply.Status.Msg))
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"container/list"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"sync"
+	"time"
+)
+
+// UqlCache caches the responses of read-only uql by graph and normalized uql,
+// entries expire after TTL, and the least recently used entry is evicted when Size is reached.
+// Cached responses are shared by callers and should not be modified.
+type UqlCache struct {
+	Size int           // max entries
+	TTL  time.Duration // 0 means entries never expire
+
+	lock    sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+	version uint64 // increased by every invalidation
+}
+
+type uqlCacheEntry struct {
+	key      string
+	graph    string
+	resp     *http.UQLResponse
+	expireAt time.Time
+}
+
+var DefaultUqlCacheSize = 1000
+
+func NewUqlCache(size int, ttl time.Duration) *UqlCache {
+	if size <= 0 {
+		size = DefaultUqlCacheSize
+	}
+	return &UqlCache{
+		Size:    size,
+		TTL:     ttl,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+func uqlCacheKey(graph string, uql string) string {
+	return graph + "\x00" + utils.NormalizeUql(uql)
+}
+
+// Get returns the cached response of uql on graph, if it exists and is not expired
+func (c *UqlCache) Get(graph string, uql string) (*http.UQLResponse, bool) {
+	key := uqlCacheKey(graph, uql)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*uqlCacheEntry)
+	if !entry.expireAt.IsZero() && time.Now().After(entry.expireAt) {
+		c.removeElement(element)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.resp, true
+}
+
+// Set caches the response of uql on graph
+func (c *UqlCache) Set(graph string, uql string, resp *http.UQLResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.set(graph, uql, resp)
+}
+
+// setSince caches the response only if no invalidation happened after version, so a read running concurrently with a write is not cached
+func (c *UqlCache) setSince(version uint64, graph string, uql string, resp *http.UQLResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.version != version {
+		return
+	}
+	c.set(graph, uql, resp)
+}
+
+func (c *UqlCache) set(graph string, uql string, resp *http.UQLResponse) {
+	key := uqlCacheKey(graph, uql)
+	entry := &uqlCacheEntry{key: key, graph: graph, resp: resp}
+	if c.TTL > 0 {
+		entry.expireAt = time.Now().Add(c.TTL)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.Size {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+func (c *UqlCache) removeElement(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*uqlCacheEntry).key)
+}
+
+func (c *UqlCache) currentVersion() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.version
+}
+
+// InvalidateGraph removes all the cached responses of graph
+func (c *UqlCache) InvalidateGraph(graph string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.version++
+	for element := c.lru.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*uqlCacheEntry).graph == graph {
+			c.removeElement(element)
+		}
+		element = next
+	}
+}
+
+// Clear removes all the cached responses
+func (c *UqlCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.version++
+	c.lru.Init()
+	c.entries = map[string]*list.Element{}
+}
+
+// Len returns the number of cached responses, including expired ones not yet removed
+func (c *UqlCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.lru.Len()
+}
+
+// queryCacheFor returns the cache, graph and cache version if the response of uql is able to be cached
+func (api *UltipaAPI) queryCacheFor(uql string, config *configuration.RequestConfig) (*UqlCache, string, uint64) {
+	if api.QueryCache == nil {
+		return nil, "", 0
+	}
+	graph := api.Config.CurrentGraph
+	if config != nil {
+		if config.NoCache || config.Host != "" {
+			return nil, "", 0
+		}
+		if config.GraphName != "" {
+			graph = config.GraphName
+		}
+	}
+	uqlClass := utils.ClassifyUql(uql)
+	if uqlClass.Write || uqlClass.Global || uqlClass.Extra || uqlClass.ExecTask {
+		return nil, "", 0
+	}
+	return api.QueryCache, graph, api.QueryCache.currentVersion()
+}
+
+// invalidateQueryCache removes the cached responses that a write uql may change,
+// graph is the graph of the request, which is used if the uql does not target a graph, e.g. truncate().graph("name")
+func (api *UltipaAPI) invalidateQueryCache(uqlItem *utils.UqlItem, graph string) {
+	if api.QueryCache == nil || !uqlItem.HasWrite() {
+		return
+	}
+	if uqlItem.IsGlobal() {
+		api.QueryCache.Clear()
+		return
+	}
+	if target := uqlItem.Classify().Graph; target != "" {
+		graph = target
+	}
+	api.QueryCache.InvalidateGraph(graph)
+}
+
+// invalidateGraphCache removes the cached responses of graph, which is written by an insert request
+func (api *UltipaAPI) invalidateGraphCache(graph string) {
+	if api.QueryCache != nil {
+		api.QueryCache.InvalidateGraph(graph)
+	}
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"errors"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"strings"
+)
+
+func (api *UltipaAPI) ListUsers(config *configuration.RequestConfig) ([]*structs.User, error) {
+	resp, err := api.UQL("show().user()", config)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, errors.New(resp.Status.Message)
+	}
+	return resp.Alias(http.RESP_USER_KEY).AsUsers()
+}
+
+func (api *UltipaAPI) GetUser(username string, config *configuration.RequestConfig) (*structs.User, error) {
+	resp, err := api.UQLWithParams("show().user($name)", map[string]interface{}{
+		"name": username,
+	}, config)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, errors.New(resp.Status.Message)
+	}
+	users, err := resp.Alias(http.RESP_USER_KEY).AsUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found: " + username)
+}
+
+// FormatGraphPrivileges formats graph privileges as {"graph": ["PRIVILEGE"]}, graph names are quoted as strings
+func FormatGraphPrivileges(graphPrivileges structs.GraphPrivileges) (utils.UqlRaw, error) {
+	var items []string
+	for _, graph := range graphPrivileges.Graphs() {
+		privileges := graphPrivileges[graph]
+		if privileges == nil {
+			privileges = []string{}
+		}
+		value, err := utils.FormatUqlValue(privileges)
+		if err != nil {
+			return "", err
+		}
+		items = append(items, utils.QuoteUqlString(graph)+": "+value)
+	}
+	return utils.UqlRaw("{" + strings.Join(items, ", ") + "}"), nil
+}
+
+// FormatPropertyPrivileges formats property privileges as {"node": {"read": [["graph", "schema", "property"]], "write": [], "deny": []}, "edge": {...}}
+func FormatPropertyPrivileges(propertyPrivileges *structs.PropertyPrivileges) (utils.UqlRaw, error) {
+	if propertyPrivileges == nil {
+		propertyPrivileges = &structs.PropertyPrivileges{}
+	}
+	var items []string
+	for _, dbType := range []struct {
+		name      string
+		privilege structs.PropertyPrivilege
+	}{
+		{"node", propertyPrivileges.Node},
+		{"edge", propertyPrivileges.Edge},
+	} {
+		var fields []string
+		for _, field := range []struct {
+			name  string
+			items [][]string
+		}{
+			{"read", dbType.privilege.Read},
+			{"write", dbType.privilege.Write},
+			{"deny", dbType.privilege.Deny},
+		} {
+			if field.items == nil {
+				field.items = [][]string{}
+			}
+			value, err := utils.FormatUqlValue(field.items)
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, utils.QuoteUqlString(field.name)+": "+value)
+		}
+		items = append(items, utils.QuoteUqlString(dbType.name)+": {"+strings.Join(fields, ", ")+"}")
+	}
+	return utils.UqlRaw("{" + strings.Join(items, ", ") + "}"), nil
+}
+
+// privilegeParams builds {graph_privileges, system_privileges, property_privileges, policies}, nil fields are skipped
+func privilegeParams(privileges *structs.PrivilegeSet) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	if privileges == nil {
+		return params, nil
+	}
+	if privileges.GraphPrivileges != nil {
+		formatted, err := FormatGraphPrivileges(privileges.GraphPrivileges)
+		if err != nil {
+			return nil, err
+		}
+		params["graph_privileges"] = formatted
+	}
+	if privileges.SystemPrivileges != nil {
+		params["system_privileges"] = privileges.SystemPrivileges
+	}
+	if privileges.PropertyPrivileges != nil {
+		formatted, err := FormatPropertyPrivileges(privileges.PropertyPrivileges)
+		if err != nil {
+			return nil, err
+		}
+		params["property_privileges"] = formatted
+	}
+	if privileges.Policies != nil {
+		params["policies"] = privileges.Policies
+	}
+	return params, nil
+}
+
+// requirePropertyPrivileges fails early if property privileges are set but not supported by the server
+func (api *UltipaAPI) requirePropertyPrivileges(privileges *structs.PrivilegeSet) error {
+	if privileges != nil && privileges.PropertyPrivileges != nil {
+		return api.requireFeature(FeaturePropertyPrivileges)
+	}
+	return nil
+}
+
+// CreateUser creates user with its password, privileges and policies
+func (api *UltipaAPI) CreateUser(user *structs.User, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	if user.Password == "" {
+		return nil, errors.New("password is required to create user " + user.Username)
+	}
+	if err := api.requirePropertyPrivileges(user.PrivilegeSet()); err != nil {
+		return nil, err
+	}
+	params, err := privilegeParams(user.PrivilegeSet())
+	if err != nil {
+		return nil, err
+	}
+	uql := "create().user($name, $password)"
+	if len(params) > 0 {
+		uql += ".params($params)"
+	}
+	return checkUQLResponse(api.UQLWithParams(uql, map[string]interface{}{
+		"name":     user.Username,
+		"password": user.Password,
+		"params":   params,
+	}, config))
+}
+
+// AlterUser sets the password if it is not empty, and the privileges and policies which are not nil
+func (api *UltipaAPI) AlterUser(user *structs.User, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	if err := api.requirePropertyPrivileges(user.PrivilegeSet()); err != nil {
+		return nil, err
+	}
+	values, err := privilegeParams(user.PrivilegeSet())
+	if err != nil {
+		return nil, err
+	}
+	if user.Password != "" {
+		values["password"] = user.Password
+	}
+	if len(values) == 0 {
+		return nil, errors.New("nothing to alter for user " + user.Username)
+	}
+	return checkUQLResponse(api.UQLWithParams("alter().user($name).set($values)", map[string]interface{}{
+		"name":   user.Username,
+		"values": values,
+	}, config))
+}
+
+func (api *UltipaAPI) DropUser(username string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	return checkUQLResponse(api.UQLWithParams("drop().user($name)", map[string]interface{}{
+		"name": username,
+	}, config))
+}
+
+// GrantToUser grants privileges and policies to user, nil arguments are skipped
+func (api *UltipaAPI) GrantToUser(username string, graphPrivileges structs.GraphPrivileges, systemPrivileges []string, policies []string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	return api.grantOrRevoke("grant", "user", username, &structs.PrivilegeSet{
+		GraphPrivileges:  graphPrivileges,
+		SystemPrivileges: systemPrivileges,
+		Policies:         policies,
+	}, config)
+}
+
+// RevokeFromUser revokes privileges and policies from user, nil arguments are skipped
+func (api *UltipaAPI) RevokeFromUser(username string, graphPrivileges structs.GraphPrivileges, systemPrivileges []string, policies []string, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	return api.grantOrRevoke("revoke", "user", username, &structs.PrivilegeSet{
+		GraphPrivileges:  graphPrivileges,
+		SystemPrivileges: systemPrivileges,
+		Policies:         policies,
+	}, config)
+}
+
+// grantOrRevoke runs grant() or revoke() of a user or a policy, target is "user" or "policy"
+func (api *UltipaAPI) grantOrRevoke(command string, target string, name string, privileges *structs.PrivilegeSet, config *configuration.RequestConfig) (*http.UQLResponse, error) {
+	if err := api.requirePropertyPrivileges(privileges); err != nil {
+		return nil, err
+	}
+	uql, err := grantOrRevokeUql(command, target, name, privileges)
+	if err != nil {
+		return nil, err
+	}
+	return checkUQLResponse(api.UQL(uql, config))
+}
+
+func grantOrRevokeUql(command string, target string, name string, privileges *structs.PrivilegeSet) (string, error) {
+	params, err := privilegeParams(privileges)
+	if err != nil {
+		return "", err
+	}
+	if len(params) == 0 {
+		return "", errors.New("nothing to " + command + " for " + target + " " + name)
+	}
+	return utils.BindUqlParams(command+"()."+target+"($name).params($params)", map[string]interface{}{
+		"name":   name,
+		"params": params,
+	})
+}
+
+// checkUQLResponse turns a failed status into an error, the response is returned as well
+func checkUQLResponse(resp *http.UQLResponse, err error) (*http.UQLResponse, error) {
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return resp, errors.New(resp.Status.Message)
+	}
+	return resp, nil
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+)
+
+// GetUserSetting returns the setting of key saved in server, empty if it is not set, username is the current user if it is empty
+func (api *UltipaAPI) GetUserSetting(username string, key string, config *configuration.RequestConfig) (string, error) {
+	reply, err := api.userSetting(&ultipa.UserSettingRequest{
+		UserName: username,
+		Opt:      ultipa.UserSettingRequest_OPT_GET,
+		Type:     key,
+	}, config)
+	if err != nil {
+		return "", err
+	}
+	return reply.Data, nil
+}
+
+// SetUserSetting saves the setting of key in server, username is the current user if it is empty
+func (api *UltipaAPI) SetUserSetting(username string, key string, data string, config *configuration.RequestConfig) error {
+	_, err := api.userSetting(&ultipa.UserSettingRequest{
+		UserName: username,
+		Opt:      ultipa.UserSettingRequest_OPT_SET,
+		Type:     key,
+		Data:     data,
+	}, config)
+	return err
+}
+
+// LoadUserSettingJSON decodes the json setting of key into value, returns false if the setting is not set
+// Usage: var queries []SavedQuery; found, err := LoadUserSettingJSON("", "saved_queries", &queries, nil)
+func (api *UltipaAPI) LoadUserSettingJSON(username string, key string, value interface{}, config *configuration.RequestConfig) (bool, error) {
+	data, err := api.GetUserSetting(username, key, config)
+	if err != nil {
+		return false, err
+	}
+	if data == "" {
+		return false, nil
+	}
+	if err = json.Unmarshal([]byte(data), value); err != nil {
+		return true, errors.New(fmt.Sprintf("failed to decode user setting %s: %v", key, err))
+	}
+	return true, nil
+}
+
+// SaveUserSettingJSON encodes value as json and saves it as the setting of key
+func (api *UltipaAPI) SaveUserSettingJSON(username string, key string, value interface{}, config *configuration.RequestConfig) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return errors.New(fmt.Sprintf("failed to encode user setting %s: %v", key, err))
+	}
+	return api.SetUserSetting(username, key, string(data), config)
+}
+
+func (api *UltipaAPI) userSetting(request *ultipa.UserSettingRequest, config *configuration.RequestConfig) (*ultipa.UserSettingReply, error) {
+	if request.Type == "" {
+		return nil, errors.New("user setting key can not be empty")
+	}
+	if request.UserName == "" {
+		request.UserName = api.Config.Username
+	}
+	if err := api.requireFeature(FeatureUserSetting); err != nil {
+		return nil, err
+	}
+
+	client, err := api.GetControlClient(config)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel, err := api.Pool.NewContext(config)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	reply, err := client.UserSetting(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if reply.Status != nil && reply.Status.ErrorCode != ultipa.ErrorCode_SUCCESS {
+		return nil, errors.New(fmt.Sprintf("failed to %s user setting %s of %s: %s", request.Opt, request.Type, request.UserName, reply.Status.Msg))
+	}
+	return reply, nil
+}
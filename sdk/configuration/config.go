@@ -24,7 +24,14 @@ type UltipaConfig struct {
 	CurrentClusterId string   `yaml:"current_cluster_id"` // used for name server only
 	Timeout          int32    // timeout - seconds
 	Debug            bool     // debug, print more logs
-	HeartBeat        int      `yaml:"heart_beat"` // frequency:second,  if 0 means no heart beat, to make sure the connection is alive
+	HeartBeat        int      `yaml:"heart_beat"`       // frequency:second,  if 0 means no heart beat, to make sure the connection is alive
+	QueryCacheSize   int      `yaml:"query_cache_size"` // max cached responses of read-only uql, if 0 means no cache
+	QueryCacheTTL    int      `yaml:"query_cache_ttl"`  // seconds that a cached response lives, if 0 means until invalidated or evicted
+
+	SlowQueryThreshold  int    `yaml:"slow_query_threshold"`    // milliseconds, uql whose latency or total cost reaches it is recorded, if 0 means no slow query log
+	SlowQueryLogFile    string `yaml:"slow_query_log_file"`     // file of slow query records, json lines, if empty means writing records into the sdk log
+	SlowQueryLogMaxSize int    `yaml:"slow_query_log_max_size"` // megabytes, the log file is rotated when reaching it, if 0 means never rotating
+	SlowQueryLogBackups int    `yaml:"slow_query_log_backups"`  // rotated files to keep
 }
 
 var DefaultTimeout int32 = 1000
@@ -1,6 +1,9 @@
 package configuration
 
-import ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+import (
+	"context"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+)
 
 type RequestType = int32
 
@@ -11,18 +14,23 @@ const (
 )
 
 type RequestConfig struct {
-	GraphName      string      // Graphset Name
-	Timeout        int32       // timeout (Seconds)
-	ClusterId      string      // Name Server Only
-	Host           string      // set for force host test
-	UseMaster      bool        // Use Master( graphSet master )
-	UseControl     bool        // Use Control Node( global master )
-	RequestType    RequestType // choose connection by request type, write => master, task > algo, normal => random
-	Uql            string      // for Go Only, used for inner program
-	Timezone       string      // name of time zone , e.g. Aisa/Shanghai
-	TimezoneOffset int64       // seconds that elapse from UTC, prior to TimeZone
-	ThreadNum      uint32      // used for uql request
-	MaxPkgSize     int         // max package size in bytes, for both sending and receiving, if not set, default is 10M
+	GraphName      string          // Graphset Name
+	Timeout        int32           // timeout (Seconds)
+	ClusterId      string          // Name Server Only
+	Host           string          // set for force host test
+	UseMaster      bool            // Use Master( graphSet master )
+	UseControl     bool            // Use Control Node( global master )
+	RequestType    RequestType     // choose connection by request type, write => master, task > algo, normal => random
+	Uql            string          // for Go Only, used for inner program
+	Timezone       string          // name of time zone , e.g. Aisa/Shanghai
+	TimezoneOffset int64           // seconds that elapse from UTC, prior to TimeZone
+	ThreadNum      uint32          // used for uql request
+	MaxPkgSize     int             // max package size in bytes, for both sending and receiving, if not set, default is 10M
+	NoCache        bool            // skip the query cache of UQL
+	Profile        bool            // record the UQL into the slow query log whatever its latency is
+	Context        context.Context // parent context of the request, if nil, context.Background() is used
+	KillOnCancel   bool            // kill the uql in server by top() and kill() if Context is cancelled before the uql is finished
+	Preflight      bool            // check the permission by CanI before sending the request, utils.PermissionDeniedError is returned if it is denied
 }
 
 type InsertRequestConfig struct {
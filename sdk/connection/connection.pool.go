@@ -35,6 +35,7 @@ type ConnectionPool struct {
 	LastActivesTime time.Time
 	IsRaft          bool
 	muActiveSafely  sync.Mutex
+	muConnections   sync.RWMutex // guards Connections, which are added when the cluster info is refreshed
 }
 
 func NewConnectionPool(config *configuration.UltipaConfig) (*ConnectionPool, error) {
@@ -79,16 +80,41 @@ func (pool *ConnectionPool) CreateConnections() error {
 
 	for _, host := range pool.Config.Hosts {
 		conn, _ := NewConnection(host, pool.Config)
-		pool.Connections[host] = conn
+		pool.setConnection(host, conn)
 	}
 
 	return err
 }
 
+// GetConnection returns the connection of host, nil if there is no connection to host
+func (pool *ConnectionPool) GetConnection(host string) *Connection {
+	pool.muConnections.RLock()
+	defer pool.muConnections.RUnlock()
+	return pool.Connections[host]
+}
+
+// ConnectionsSnapshot returns a copy of Connections, which is safe to range while the cluster info is refreshed
+func (pool *ConnectionPool) ConnectionsSnapshot() map[string]*Connection {
+	pool.muConnections.RLock()
+	defer pool.muConnections.RUnlock()
+	connections := make(map[string]*Connection, len(pool.Connections))
+	for host, conn := range pool.Connections {
+		connections[host] = conn
+	}
+	return connections
+}
+
+func (pool *ConnectionPool) setConnection(host string, conn *Connection) {
+	pool.muConnections.Lock()
+	defer pool.muConnections.Unlock()
+	pool.Connections[host] = conn
+}
+
 func (pool *ConnectionPool) RefreshActivesWithSeconds(seconds int32) error {
 	pool.muActiveSafely.Lock()
 	defer pool.muActiveSafely.Unlock()
-	if time.Now().Sub(pool.LastActivesTime) <= 5*time.Second && len(pool.Connections) == len(pool.Actives) {
+	snapshot := pool.ConnectionsSnapshot()
+	if time.Now().Sub(pool.LastActivesTime) <= 5*time.Second && len(snapshot) == len(pool.Actives) {
 		// 避免频繁刷新
 		return nil
 	}
@@ -100,9 +126,9 @@ func (pool *ConnectionPool) RefreshActivesWithSeconds(seconds int32) error {
 		seconds = 3
 	}
 	var hosts []string
-	connErrors := make([]error, len(pool.Connections))
+	connErrors := make([]error, len(snapshot))
 	var connections []*Connection
-	for host, connection := range pool.Connections {
+	for host, connection := range snapshot {
 		hosts = append(hosts, host)
 		connections = append(connections, connection)
 	}
@@ -265,14 +291,14 @@ func (pool *ConnectionPool) resolveClusterInfo(graphName string, conn *Connectio
 
 	if resp.Status.ErrorCode == ultipa.ErrorCode_RAFT_REDIRECT {
 		pool.IsRaft = true
-		if pool.Connections[resp.Status.ClusterInfo.Redirect] == nil {
+		if pool.GetConnection(resp.Status.ClusterInfo.Redirect) == nil {
 			c, err := NewConnection(resp.Status.ClusterInfo.Redirect, pool.Config)
 			if err != nil {
 				return err
 			}
-			pool.Connections[resp.Status.ClusterInfo.Redirect] = c
+			pool.setConnection(resp.Status.ClusterInfo.Redirect, c)
 		}
-		pool.GraphMgr.SetLeader(graphName, pool.Connections[resp.Status.ClusterInfo.Redirect])
+		pool.GraphMgr.SetLeader(graphName, pool.GetConnection(resp.Status.ClusterInfo.Redirect))
 		err = pool.RefreshActives()
 		if err != nil {
 			return err
@@ -288,18 +314,18 @@ func (pool *ConnectionPool) resolveClusterInfo(graphName string, conn *Connectio
 
 	if resp.Status.ErrorCode == ultipa.ErrorCode_SUCCESS {
 		pool.IsRaft = true
-		c := pool.Connections[resp.Status.ClusterInfo.LeaderAddress]
+		c := pool.GetConnection(resp.Status.ClusterInfo.LeaderAddress)
 		pool.GraphMgr.SetLeader(graphName, c)
 		pool.GraphMgr.ClearFollower(graphName)
 		for _, follower := range resp.Status.ClusterInfo.Followers {
-			fconn := pool.Connections[follower.Address]
+			fconn := pool.GetConnection(follower.Address)
 			if fconn == nil {
 				fconn2, err5 := NewConnection(follower.Address, pool.Config)
 				if err5 != nil {
 					continue
 				}
 				fconn = fconn2
-				pool.Connections[follower.Address] = fconn
+				pool.setConnection(follower.Address, fconn)
 			}
 			fconn.Host = follower.Address
 			fconn.Active = follower.Status
@@ -395,7 +421,7 @@ func (pool *ConnectionPool) GetAnalyticsConn(config *configuration.UltipaConfig)
 }
 
 func (pool *ConnectionPool) Close() error {
-	for _, conn := range pool.Connections {
+	for _, conn := range pool.ConnectionsSnapshot() {
 		err := conn.Close()
 		if err != nil {
 			return err
@@ -426,14 +452,18 @@ func (pool *ConnectionPool) NewContext(config *configuration.RequestConfig) (ctx
 		timeout = configuration.DefaultTimeout
 	}
 
+	parentCtx := context.Background()
+	if config.Context != nil {
+		parentCtx = config.Context
+	}
+
 	if timeout < 0 {
-		parentCtx := context.Background()
 		ctx, cancel = context.WithCancel(parentCtx)
 	} else {
 		if timeout < 10 {
 			timeout = 10
 		}
-		ctx, cancel = context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+		ctx, cancel = context.WithTimeout(parentCtx, time.Duration(timeout)*time.Second)
 	}
 	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs(pool.Config.ToContextKV(config)...))
 	return ctx, cancel, nil
@@ -446,7 +476,7 @@ func (pool *ConnectionPool) RunHeartBeat() {
 		go func() {
 			for {
 				//log.Println("Heart Beat Start... ")
-				for _, conn := range pool.Connections {
+				for _, conn := range pool.ConnectionsSnapshot() {
 
 					ctx, cancel, err := pool.NewContext(&configuration.RequestConfig{
 						Timeout: 6,
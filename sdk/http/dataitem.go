@@ -24,38 +24,14 @@ func NewDataItem() *DataItem {
 
 func NodeTableToNodes(nt *ultipa.EntityTable, alias string) ([]*structs.Node, map[string]*structs.Schema, error) {
 
-	schemas := map[string]*structs.Schema{}
+	schemas := parseEntitySchemas(nt, ultipa.DBType_DBNODE)
 	nodes := []*structs.Node{}
 
-	for _, oSchema := range nt.Schemas {
-		schema := structs.NewSchema(oSchema.SchemaName)
-		schema.DBType = ultipa.DBType_DBNODE
-		schemas[schema.Name] = schema
-		for _, header := range oSchema.Properties {
-			schema.Properties = append(schema.Properties, &structs.Property{Name: header.PropertyName, Type: header.PropertyType, SubTypes: header.SubTypes})
-		}
-	}
-
 	for _, oNode := range nt.EntityRows {
-		node := &structs.Node{
-			Name:   alias,
-			ID:     oNode.Id,
-			UUID:   oNode.Uuid,
-			Schema: oNode.SchemaName,
-		}
-
-		// set values
-		node.Values = structs.NewValues()
-		schema := schemas[oNode.SchemaName]
-		for index, v := range oNode.Values {
-			prop := schema.Properties[index]
-			value, err := utils.ConvertBytesToInterface(v, prop.Type, prop.SubTypes)
-			if err != nil {
-				return nil, nil, err
-			}
-			node.Values.Set(prop.Name, value)
+		node, err := entityRowToNode(oNode, schemas, alias)
+		if err != nil {
+			return nil, nil, err
 		}
-
 		nodes = append(nodes, node)
 	}
 
@@ -64,45 +40,91 @@ func NodeTableToNodes(nt *ultipa.EntityTable, alias string) ([]*structs.Node, ma
 
 func EdgeTableToEdges(et *ultipa.EntityTable, alias string) ([]*structs.Edge, map[string]*structs.Schema, error) {
 
-	schemas := map[string]*structs.Schema{}
+	schemas := parseEntitySchemas(et, ultipa.DBType_DBEDGE)
 	edges := []*structs.Edge{}
 
-	for _, oSchema := range et.Schemas {
+	for _, oEdge := range et.EntityRows {
+		edge, err := entityRowToEdge(oEdge, schemas, alias)
+		if err != nil {
+			return nil, nil, err
+		}
+		edges = append(edges, edge)
+	}
+	return edges, schemas, nil
+}
+
+func parseEntitySchemas(et *ultipa.EntityTable, dbType ultipa.DBType) map[string]*structs.Schema {
+	schemas := map[string]*structs.Schema{}
+
+	for _, oSchema := range et.GetSchemas() {
 		schema := structs.NewSchema(oSchema.SchemaName)
-		schema.DBType = ultipa.DBType_DBEDGE
+		schema.DBType = dbType
 		schemas[schema.Name] = schema
 		for _, header := range oSchema.Properties {
 			schema.Properties = append(schema.Properties, &structs.Property{Name: header.PropertyName, Type: header.PropertyType, SubTypes: header.SubTypes})
 		}
 	}
 
-	for _, oEdge := range et.EntityRows {
-		edge := &structs.Edge{
-			Name:     alias,
-			UUID:     oEdge.Uuid,
-			From:     oEdge.FromId,
-			FromUUID: oEdge.FromUuid,
-			To:       oEdge.ToId,
-			ToUUID:   oEdge.ToUuid,
-			Schema:   oEdge.SchemaName,
-		}
-
-		// set values
-		edge.Values = structs.NewValues()
-		schema := schemas[oEdge.SchemaName]
-		for index, v := range oEdge.Values {
-			prop := schema.Properties[index]
-			value, err := utils.ConvertBytesToInterface(v, prop.Type, prop.SubTypes)
-			if err != nil {
-				return nil, nil, err
-			}
-			edge.Values.Set(prop.Name, value)
+	return schemas
+}
+
+// entityValues decodes the values of an entity row by the properties of its schema
+func entityValues(row *ultipa.EntityRow, schemas map[string]*structs.Schema) (*structs.Values, error) {
+	values := structs.NewValues()
+	if len(row.Values) == 0 {
+		return values, nil
+	}
+	schema := schemas[row.SchemaName]
+	if schema == nil {
+		return nil, errors.New(fmt.Sprintf("schema %s is not found in the reply", row.SchemaName))
+	}
+	for index, v := range row.Values {
+		if index >= len(schema.Properties) {
+			return nil, errors.New(fmt.Sprintf("schema %s has %d properties, but got %d values", row.SchemaName, len(schema.Properties), len(row.Values)))
 		}
+		prop := schema.Properties[index]
+		value, err := utils.ConvertBytesToInterface(v, prop.Type, prop.SubTypes)
+		if err != nil {
+			return nil, err
+		}
+		values.Set(prop.Name, value)
+	}
+	return values, nil
+}
 
-		edges = append(edges, edge)
+func entityRowToNode(row *ultipa.EntityRow, schemas map[string]*structs.Schema, alias string) (*structs.Node, error) {
+	node := &structs.Node{
+		Name:   alias,
+		ID:     row.Id,
+		UUID:   row.Uuid,
+		Schema: row.SchemaName,
+	}
 
+	values, err := entityValues(row, schemas)
+	if err != nil {
+		return nil, err
 	}
-	return edges, schemas, nil
+	node.Values = values
+	return node, nil
+}
+
+func entityRowToEdge(row *ultipa.EntityRow, schemas map[string]*structs.Schema, alias string) (*structs.Edge, error) {
+	edge := &structs.Edge{
+		Name:     alias,
+		UUID:     row.Uuid,
+		From:     row.FromId,
+		FromUUID: row.FromUuid,
+		To:       row.ToId,
+		ToUUID:   row.ToUuid,
+		Schema:   row.SchemaName,
+	}
+
+	values, err := entityValues(row, schemas)
+	if err != nil {
+		return nil, err
+	}
+	edge.Values = values
+	return edge, nil
 }
 
 func (di *DataItem) AsNodes() (nodes []*structs.Node, schemas map[string]*structs.Schema, err error) {
@@ -161,13 +183,7 @@ func (di *DataItem) AsPaths() (paths []*structs.Path, err error) {
 
 func parsePaths(oPaths []*ultipa.Path, name string) (paths []*structs.Path, err error) {
 	for _, oPath := range oPaths {
-		path := structs.NewPath()
-		path.Name = name
-		path.Nodes, path.NodeSchemas, err = NodeTableToNodes(oPath.NodeTable, path.Name)
-		if err != nil {
-			return nil, err
-		}
-		path.Edges, path.EdgeSchemas, err = EdgeTableToEdges(oPath.EdgeTable, path.Name)
+		path, err := parsePath(oPath, name)
 		if err != nil {
 			return nil, err
 		}
@@ -176,6 +192,20 @@ func parsePaths(oPaths []*ultipa.Path, name string) (paths []*structs.Path, err
 	return paths, nil
 }
 
+func parsePath(oPath *ultipa.Path, name string) (path *structs.Path, err error) {
+	path = structs.NewPath()
+	path.Name = name
+	path.Nodes, path.NodeSchemas, err = NodeTableToNodes(oPath.NodeTable, path.Name)
+	if err != nil {
+		return nil, err
+	}
+	path.Edges, path.EdgeSchemas, err = EdgeTableToEdges(oPath.EdgeTable, path.Name)
+	if err != nil {
+		return nil, err
+	}
+	return path, nil
+}
+
 func (di *DataItem) AsTable() (table *structs.Table, err error) {
 
 	if di.Type == ultipa.ResultType_RESULT_TYPE_UNSET {
@@ -190,31 +220,46 @@ func (di *DataItem) AsTable() (table *structs.Table, err error) {
 
 	table = structs.NewTable()
 	table.Name = oTable.TableName
+	table.Headers = parseTableHeaders(oTable)
+
+	for _, row := range oTable.TableRows {
+		r, err := tableRowToRow(row, table.Headers)
+		if err != nil {
+			return nil, err
+		}
+		table.Rows = append(table.Rows, r)
+	}
+
+	return table, err
+}
 
+func parseTableHeaders(oTable *ultipa.Table) []*structs.Property {
+	var headers []*structs.Property
 	for _, header := range oTable.Headers {
 		h := &structs.Property{
 			Name: header.PropertyName,
 			Type: header.PropertyType,
 		}
-		table.Headers = append(table.Headers, h)
+		headers = append(headers, h)
 	}
+	return headers
+}
 
-	for _, row := range oTable.TableRows {
-
-		r := structs.Row{}
+func tableRowToRow(row *ultipa.TableRow, headers []*structs.Property) (*structs.Row, error) {
+	r := structs.Row{}
 
-		for index, field := range row.Values {
-			value, err := utils.ConvertBytesToInterface(field, table.Headers[index].Type, table.Headers[index].SubTypes)
-			if err != nil {
-				return nil, err
-			}
-			r = append(r, value)
+	for index, field := range row.Values {
+		if index >= len(headers) {
+			return nil, errors.New(fmt.Sprintf("table has %d headers, but got %d values", len(headers), len(row.Values)))
 		}
-
-		table.Rows = append(table.Rows, &r)
+		value, err := utils.ConvertBytesToInterface(field, headers[index].Type, headers[index].SubTypes)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, value)
 	}
 
-	return table, err
+	return &r, nil
 }
 
 //AsArray find().nodes() as nodes group by nodes.year as y return y,collect(nodes._id)
@@ -263,6 +308,10 @@ func (di *DataItem) AsAttr() (*structs.Attr, error) {
 	}
 
 	attrAlias := di.Data.(*ultipa.AttrAlias)
+	return attrAliasToAttr(attrAlias, di.Alias)
+}
+
+func attrAliasToAttr(attrAlias *ultipa.AttrAlias, alias string) (*structs.Attr, error) {
 	oAttr := attrAlias.Attr
 
 	midAttr, err := parseAttr(oAttr, attrAlias.Alias)
@@ -274,13 +323,12 @@ func (di *DataItem) AsAttr() (*structs.Attr, error) {
 		return midAttr.ListAttrAsAttr()
 
 	case ultipa.PropertyType_SET:
-		return nil, errors.New(fmt.Sprintf("DataItem %v is not either Type Attr or LIST Attr, but SET, not supported yet.", di.Alias))
+		return nil, errors.New(fmt.Sprintf("DataItem %v is not either Type Attr or LIST Attr, but SET, not supported yet.", alias))
 	case ultipa.PropertyType_MAP:
-		return nil, errors.New(fmt.Sprintf("DataItem %v is not either Type Attr or LIST Attr, but MAP, not supported yet.", di.Alias))
+		return nil, errors.New(fmt.Sprintf("DataItem %v is not either Type Attr or LIST Attr, but MAP, not supported yet.", alias))
 	default:
 		return midAttr, nil
 	}
-	return nil, err
 }
 
 // AsAttrEdges parse DataItem as Attr with Rows that is List<List<Node>>
@@ -747,6 +795,231 @@ func (di *DataItem) AsAlgos() ([]*structs.Algo, error) {
 	return algos, nil
 }
 
+// AsExtas converts the _extaList table of show().exta() to extas
+func (di *DataItem) AsExtas() ([]*structs.Exta, error) {
+
+	if di.Type != ultipa.ResultType_RESULT_TYPE_TABLE {
+		return nil, errors.New("DataItem " + di.Alias + " should be a table(exta) as pre-condition")
+	}
+
+	table, err := di.AsTable()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if table.Name != RESP_EXTAS_KEY {
+		return nil, errors.New("DataItem " + di.Alias + " is not an exta list")
+	}
+
+	var extas []*structs.Exta
+
+	for _, extaData := range table.ToKV() {
+		extas = append(extas, &structs.Exta{
+			Name:    tableCellString(extaData.Get("name")),
+			Version: tableCellString(extaData.Get("version")),
+			Author:  tableCellString(extaData.Get("author")),
+			Detail:  tableCellString(extaData.Get("detail")),
+		})
+	}
+
+	return extas, nil
+}
+
+// AsTasks converts the _task table of show().task() to tasks
+func (di *DataItem) AsTasks() ([]*structs.Task, error) {
+
+	if di.Type != ultipa.ResultType_RESULT_TYPE_TABLE {
+		return nil, errors.New("DataItem " + di.Alias + " should be a table(task) as pre-condition")
+	}
+
+	table, err := di.AsTable()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if table.Name != RESP_TASK_KEY {
+		return nil, errors.New("DataItem " + di.Alias + " is not a task list")
+	}
+
+	var tasks []*structs.Task
+
+	for _, taskData := range table.ToKV() {
+
+		task, err := structs.NewTask(tableCellString(taskData.Get("task_info")), tableCellString(taskData.Get("param")), tableCellString(taskData.Get("result")))
+
+		if err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// AsProcesses converts the _top table of top() to processes
+func (di *DataItem) AsProcesses() ([]*structs.Process, error) {
+
+	if di.Type != ultipa.ResultType_RESULT_TYPE_TABLE {
+		return nil, errors.New("DataItem " + di.Alias + " should be a table(top) as pre-condition")
+	}
+
+	table, err := di.AsTable()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if table.Name != RESP_TOP_KEY {
+		return nil, errors.New("DataItem " + di.Alias + " is not a process list")
+	}
+
+	var processes []*structs.Process
+
+	for _, processData := range table.ToKV() {
+		processes = append(processes, &structs.Process{
+			Id:       tableCellString(processData.Get("process_id")),
+			Uql:      tableCellString(processData.Get("process_uql")),
+			Duration: tableCellString(processData.Get("duration")),
+			Status:   tableCellString(processData.Get("status")),
+		})
+	}
+
+	return processes, nil
+}
+
+// AsUsers converts the _user table of show().user() to users
+func (di *DataItem) AsUsers() ([]*structs.User, error) {
+
+	if di.Type != ultipa.ResultType_RESULT_TYPE_TABLE {
+		return nil, errors.New("DataItem " + di.Alias + " should be a table(user) as pre-condition")
+	}
+
+	table, err := di.AsTable()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if table.Name != RESP_USER_KEY {
+		return nil, errors.New("DataItem " + di.Alias + " is not a user list")
+	}
+
+	var users []*structs.User
+
+	for _, userData := range table.ToKV() {
+		user, err := structs.NewUser(
+			tableCellString(userData.Get("username")),
+			tableCellString(userData.Get("create")),
+			tableCellString(userData.Get("graphPrivileges")),
+			tableCellString(userData.Get("systemPrivileges")),
+			tableCellString(userData.Get("policies")),
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if propertyPrivileges := tableCellString(userData.Get("propertyPrivileges")); propertyPrivileges != "" {
+			user.PropertyPrivileges, err = structs.ParsePropertyPrivileges(propertyPrivileges)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("failed to parse property privileges of user %s: %v", user.Username, err))
+			}
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// AsPolicies converts the _policy table of show().policy() to policies
+func (di *DataItem) AsPolicies() ([]*structs.Policy, error) {
+
+	if di.Type != ultipa.ResultType_RESULT_TYPE_TABLE {
+		return nil, errors.New("DataItem " + di.Alias + " should be a table(policy) as pre-condition")
+	}
+
+	table, err := di.AsTable()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if table.Name != RESP_POLICY_KEY {
+		return nil, errors.New("DataItem " + di.Alias + " is not a policy list")
+	}
+
+	var policies []*structs.Policy
+
+	for _, policyData := range table.ToKV() {
+		policy, err := structs.NewPolicy(
+			tableCellString(policyData.Get("name")),
+			tableCellString(policyData.Get("graphPrivileges")),
+			tableCellString(policyData.Get("systemPrivileges")),
+			tableCellString(policyData.Get("propertyPrivileges")),
+			tableCellString(policyData.Get("policies")),
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// AsPrivileges converts the _privilege table of show().privilege() to privileges
+func (di *DataItem) AsPrivileges() ([]*structs.Privilege, error) {
+
+	if di.Type != ultipa.ResultType_RESULT_TYPE_TABLE {
+		return nil, errors.New("DataItem " + di.Alias + " should be a table(privilege) as pre-condition")
+	}
+
+	table, err := di.AsTable()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if table.Name != RESP_PRIVILEGE_KEY {
+		return nil, errors.New("DataItem " + di.Alias + " is not a privilege list")
+	}
+
+	var privileges []*structs.Privilege
+
+	for _, privilegeData := range table.ToKV() {
+		rowPrivileges, err := structs.NewPrivileges(
+			tableCellString(privilegeData.Get("graphPrivileges")),
+			tableCellString(privilegeData.Get("systemPrivileges")),
+			tableCellString(privilegeData.Get("propertyPrivileges")),
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		privileges = append(privileges, rowPrivileges...)
+	}
+
+	return privileges, nil
+}
+
+// tableCellString converts a cell of the system tables to string, nil is empty
+func tableCellString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if str, ok := v.(string); ok {
+		return str
+	}
+	return fmt.Sprint(v)
+}
+
 func (di *DataItem) AsAny() (interface{}, error) {
 
 	switch di.Type {
@@ -0,0 +1,464 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/types"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScanTag is the struct tag to map a property to a field, e.g. `ultipa:"name"`, `ultipa:"-"` skips the field,
+// fields without the tag are mapped by the field name case-insensitively.
+// Besides properties, nodes provide _id, _uuid, _schema, edges provide _uuid, _from, _to, _from_uuid, _to_uuid, _schema
+const ScanTag = "ultipa"
+
+var (
+	timeType         = reflect.TypeOf(time.Time{})
+	ultipaTimeType   = reflect.TypeOf(utils.UltipaTime{})
+	pointType        = reflect.TypeOf(types.Point{})
+	scanFieldsCache  sync.Map // reflect.Type => *scanFields
+	errScanNotStruct = errors.New("destination must be a pointer to a slice of structs or struct pointers")
+)
+
+type scanField struct {
+	name  string
+	index []int
+}
+
+type scanFields struct {
+	fields []*scanField
+	byName map[string]*scanField
+	byLow  map[string]*scanField
+}
+
+// get finds the field by name, the exact name is preferred
+func (f *scanFields) get(name string) *scanField {
+	if field, ok := f.byName[name]; ok {
+		return field
+	}
+	return f.byLow[strings.ToLower(name)]
+}
+
+// ScanNodes maps the nodes to dest, which is a pointer to a slice of structs or struct pointers
+// Usage:
+//
+//	type Person struct {
+//		ID       string    `ultipa:"_id"`
+//		Name     string    `ultipa:"name"`
+//		Birthday time.Time `ultipa:"birthday"`
+//	}
+//	var persons []Person
+//	err := resp.Alias("n").ScanNodes(&persons)
+func (di *DataItem) ScanNodes(dest interface{}) error {
+	nodes, _, err := di.AsNodes()
+	if err != nil {
+		return err
+	}
+	return scanSlice(dest, len(nodes), func(i int, elem reflect.Value) error {
+		return scanNode(nodes[i], elem)
+	})
+}
+
+// ScanEdges maps the edges to dest, which is a pointer to a slice of structs or struct pointers, check ScanNodes
+func (di *DataItem) ScanEdges(dest interface{}) error {
+	edges, _, err := di.AsEdges()
+	if err != nil {
+		return err
+	}
+	return scanSlice(dest, len(edges), func(i int, elem reflect.Value) error {
+		return scanEdge(edges[i], elem)
+	})
+}
+
+// ScanTable maps the table rows to dest by headers, dest is a pointer to a slice of structs or struct pointers, check ScanNodes
+func (di *DataItem) ScanTable(dest interface{}) error {
+	table, err := di.AsTable()
+	if err != nil {
+		return err
+	}
+	if table == nil {
+		return scanSlice(dest, 0, nil)
+	}
+	return scanSlice(dest, len(table.Rows), func(i int, elem reflect.Value) error {
+		return scanRow(table.Headers, table.Rows[i], elem)
+	})
+}
+
+// scanSlice resets the slice of dest to size elements and fills each element by scan
+func scanSlice(dest interface{}, size int, scan func(i int, elem reflect.Value) error) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Slice {
+		return errScanNotStruct
+	}
+	slice := dv.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	structType := elemType
+	if isPtr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return errScanNotStruct
+	}
+
+	result := reflect.MakeSlice(slice.Type(), size, size)
+	for i := 0; i < size; i++ {
+		elem := result.Index(i)
+		if isPtr {
+			elem.Set(reflect.New(structType))
+			elem = elem.Elem()
+		}
+		if err := scan(i, elem); err != nil {
+			return err
+		}
+	}
+	slice.Set(result)
+	return nil
+}
+
+func scanNode(node *structs.Node, target reflect.Value) error {
+	return scanStruct(target, func(name string) (interface{}, bool) {
+		switch name {
+		case "_id":
+			return node.ID, true
+		case "_uuid":
+			return node.UUID, true
+		case "_schema":
+			return node.Schema, true
+		}
+		return lookupValues(node.Values, name)
+	}, "node "+node.ID)
+}
+
+func scanEdge(edge *structs.Edge, target reflect.Value) error {
+	return scanStruct(target, func(name string) (interface{}, bool) {
+		switch name {
+		case "_uuid":
+			return edge.UUID, true
+		case "_from":
+			return edge.From, true
+		case "_to":
+			return edge.To, true
+		case "_from_uuid":
+			return edge.FromUUID, true
+		case "_to_uuid":
+			return edge.ToUUID, true
+		case "_schema":
+			return edge.Schema, true
+		}
+		return lookupValues(edge.Values, name)
+	}, fmt.Sprintf("edge %d", edge.UUID))
+}
+
+func scanRow(headers []*structs.Property, row *structs.Row, target reflect.Value) error {
+	return scanStruct(target, func(name string) (interface{}, bool) {
+		for i, header := range headers {
+			if header.Name == name && i < len(*row) {
+				return (*row)[i], true
+			}
+		}
+		for i, header := range headers {
+			if strings.EqualFold(header.Name, name) && i < len(*row) {
+				return (*row)[i], true
+			}
+		}
+		return nil, false
+	}, "table row")
+}
+
+func lookupValues(values *structs.Values, name string) (interface{}, bool) {
+	if values == nil {
+		return nil, false
+	}
+	if value, ok := values.Data[name]; ok {
+		return value, true
+	}
+	for key, value := range values.Data {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// scanStruct sets every mapped field of target by lookup, fields not found by lookup are left untouched
+func scanStruct(target reflect.Value, lookup func(name string) (interface{}, bool), source string) error {
+	fields := getScanFields(target.Type())
+	for _, field := range fields.fields {
+		value, ok := lookup(field.name)
+		if !ok {
+			continue
+		}
+		if err := ConvertScanValue(value, target.FieldByIndex(field.index)); err != nil {
+			return errors.New(fmt.Sprintf("unable to scan %s property %s into field %s.%s: %s",
+				source, field.name, target.Type().Name(), target.Type().FieldByIndex(field.index).Name, err.Error()))
+		}
+	}
+	return nil
+}
+
+func getScanFields(t reflect.Type) *scanFields {
+	if cached, ok := scanFieldsCache.Load(t); ok {
+		return cached.(*scanFields)
+	}
+	fields := &scanFields{
+		byName: map[string]*scanField{},
+		byLow:  map[string]*scanField{},
+	}
+	collectScanFields(t, nil, fields)
+	scanFieldsCache.Store(t, fields)
+	return fields
+}
+
+// collectScanFields collects the exported fields of t, fields of embedded structs are collected as well
+func collectScanFields(t reflect.Type, parent []int, fields *scanFields) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(ScanTag)
+		if tag == "-" {
+			continue
+		}
+		index := append(append([]int{}, parent...), i)
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			collectScanFields(f.Type, index, fields)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		name := tag
+		if name == "" {
+			name = f.Name
+		}
+		if _, ok := fields.byName[name]; ok {
+			continue
+		}
+		field := &scanField{name: name, index: index}
+		fields.fields = append(fields.fields, field)
+		fields.byName[name] = field
+		if _, ok := fields.byLow[strings.ToLower(name)]; !ok {
+			fields.byLow[strings.ToLower(name)] = field
+		}
+	}
+}
+
+// ConvertScanValue sets the decoded value to target:
+// UltipaTime => time.Time, types.Point => types.Point or any struct with Latitude and Longitude fields,
+// LIST => slices or arrays, numbers are converted between numeric types if not overflowed, nil => zero value
+func ConvertScanValue(value interface{}, target reflect.Value) error {
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	if target.Kind() == reflect.Interface && target.NumMethod() == 0 {
+		target.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	vv := reflect.ValueOf(value)
+	if vv.Type().AssignableTo(target.Type()) {
+		target.Set(vv)
+		return nil
+	}
+
+	if target.Kind() == reflect.Ptr {
+		if vv.Kind() == reflect.Ptr && vv.IsNil() {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		elem := reflect.New(target.Type().Elem())
+		if err := ConvertScanValue(value, elem.Elem()); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+	}
+
+	if vv.Kind() == reflect.Ptr {
+		if vv.IsNil() {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		if vv.Elem().Type().AssignableTo(target.Type()) {
+			target.Set(vv.Elem())
+			return nil
+		}
+	}
+
+	switch v := value.(type) {
+	case *utils.UltipaTime:
+		return convertUltipaTime(v, target)
+	case utils.UltipaTime:
+		return convertUltipaTime(&v, target)
+	case *types.Point:
+		return convertPoint(v, target)
+	case types.Point:
+		return convertPoint(&v, target)
+	case []byte:
+		if target.Kind() == reflect.String {
+			target.SetString(string(v))
+			return nil
+		}
+	}
+
+	switch {
+	case isIntKind(vv.Kind()) || isUintKind(vv.Kind()) || isFloatKind(vv.Kind()):
+		return convertNumber(vv, target)
+	case vv.Kind() == reflect.String && target.Kind() == reflect.String:
+		target.SetString(vv.String())
+		return nil
+	case vv.Kind() == reflect.Bool && target.Kind() == reflect.Bool:
+		target.SetBool(vv.Bool())
+		return nil
+	case vv.Kind() == reflect.Slice || vv.Kind() == reflect.Array:
+		return convertList(vv, target)
+	}
+
+	return errors.New(fmt.Sprintf("unable to convert %T to %v", value, target.Type()))
+}
+
+func convertUltipaTime(t *utils.UltipaTime, target reflect.Value) error {
+	if t.Time == nil {
+		t.Uint64ToTime(t.Datetime)
+	}
+	switch target.Type() {
+	case timeType:
+		target.Set(reflect.ValueOf(*t.Time))
+		return nil
+	case ultipaTimeType:
+		target.Set(reflect.ValueOf(*t))
+		return nil
+	}
+	if target.Kind() == reflect.String {
+		target.SetString(t.String())
+		return nil
+	}
+	return errors.New(fmt.Sprintf("unable to convert UltipaTime to %v, use time.Time, utils.UltipaTime or string", target.Type()))
+}
+
+// convertPoint sets a point to types.Point or a struct having float fields named or tagged Latitude and Longitude
+func convertPoint(p *types.Point, target reflect.Value) error {
+	if target.Type() == pointType {
+		target.Set(reflect.ValueOf(*p))
+		return nil
+	}
+	if target.Kind() == reflect.String {
+		target.SetString(p.String())
+		return nil
+	}
+	if target.Kind() == reflect.Struct {
+		fields := getScanFields(target.Type())
+		latitude, longitude := fields.get("latitude"), fields.get("longitude")
+		if latitude != nil && longitude != nil {
+			if err := ConvertScanValue(p.Latitude, target.FieldByIndex(latitude.index)); err != nil {
+				return err
+			}
+			return ConvertScanValue(p.Longitude, target.FieldByIndex(longitude.index))
+		}
+	}
+	return errors.New(fmt.Sprintf("unable to convert Point to %v, use types.Point or a struct with Latitude and Longitude fields", target.Type()))
+}
+
+func convertList(list reflect.Value, target reflect.Value) error {
+	switch target.Kind() {
+	case reflect.Slice:
+		result := reflect.MakeSlice(target.Type(), list.Len(), list.Len())
+		for i := 0; i < list.Len(); i++ {
+			if err := ConvertScanValue(list.Index(i).Interface(), result.Index(i)); err != nil {
+				return errors.New(fmt.Sprintf("element %d: %s", i, err.Error()))
+			}
+		}
+		target.Set(result)
+		return nil
+	case reflect.Array:
+		if list.Len() > target.Len() {
+			return errors.New(fmt.Sprintf("unable to convert a list of %d elements to %v", list.Len(), target.Type()))
+		}
+		target.Set(reflect.Zero(target.Type()))
+		for i := 0; i < list.Len(); i++ {
+			if err := ConvertScanValue(list.Index(i).Interface(), target.Index(i)); err != nil {
+				return errors.New(fmt.Sprintf("element %d: %s", i, err.Error()))
+			}
+		}
+		return nil
+	}
+	return errors.New(fmt.Sprintf("unable to convert %v to %v", list.Type(), target.Type()))
+}
+
+func convertNumber(number reflect.Value, target reflect.Value) error {
+	switch {
+	case isIntKind(target.Kind()):
+		var n int64
+		switch {
+		case isIntKind(number.Kind()):
+			n = number.Int()
+		case isUintKind(number.Kind()):
+			u := number.Uint()
+			if u > 1<<63-1 {
+				return errors.New(fmt.Sprintf("%v overflows %v", u, target.Type()))
+			}
+			n = int64(u)
+		default:
+			return errors.New(fmt.Sprintf("unable to convert %v to %v without losing the fraction", number.Type(), target.Type()))
+		}
+		if target.OverflowInt(n) {
+			return errors.New(fmt.Sprintf("%v overflows %v", n, target.Type()))
+		}
+		target.SetInt(n)
+		return nil
+	case isUintKind(target.Kind()):
+		var n uint64
+		switch {
+		case isUintKind(number.Kind()):
+			n = number.Uint()
+		case isIntKind(number.Kind()):
+			if number.Int() < 0 {
+				return errors.New(fmt.Sprintf("%v overflows %v", number.Int(), target.Type()))
+			}
+			n = uint64(number.Int())
+		default:
+			return errors.New(fmt.Sprintf("unable to convert %v to %v without losing the fraction", number.Type(), target.Type()))
+		}
+		if target.OverflowUint(n) {
+			return errors.New(fmt.Sprintf("%v overflows %v", n, target.Type()))
+		}
+		target.SetUint(n)
+		return nil
+	case isFloatKind(target.Kind()):
+		target.Set(number.Convert(target.Type()))
+		return nil
+	}
+	return errors.New(fmt.Sprintf("unable to convert %v to %v", number.Type(), target.Type()))
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uintptr
+}
+
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+// isScanStruct checks whether dest is a pointer to a user struct, which is scanned by tags
+func isScanStruct(dest interface{}) bool {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	switch dv.Elem().Type() {
+	case reflect.TypeOf(structs.Node{}), reflect.TypeOf(structs.Edge{}), reflect.TypeOf(structs.Path{}), reflect.TypeOf(structs.Table{}),
+		reflect.TypeOf(structs.Attr{}), timeType, ultipaTimeType, pointType:
+		return false
+	}
+	return true
+}
@@ -31,3 +31,26 @@ func ParseExplainPlan(ex *ultipa.ExplainPlan) (*ExplainPlan, error) {
 
 	return &explainPlan, nil
 }
+
+// Tree returns the explain plan as trees, usually there is only one root
+func (e *ExplainPlan) Tree() ([]*structs.ExplainNode, error) {
+	return structs.BuildExplainTree(e.Explain)
+}
+
+// ToDOT returns the explain plan as a Graphviz digraph
+func (e *ExplainPlan) ToDOT() (string, error) {
+	roots, err := e.Tree()
+	if err != nil {
+		return "", err
+	}
+	return structs.ExplainTreeToDOT(roots), nil
+}
+
+// ToMermaid returns the explain plan as a Mermaid flowchart
+func (e *ExplainPlan) ToMermaid() (string, error) {
+	roots, err := e.Tree()
+	if err != nil {
+		return "", err
+	}
+	return structs.ExplainTreeToMermaid(roots), nil
+}
@@ -0,0 +1,485 @@
+/**
+ * Canonical json encoding of UQLResponse, see doc/07.json.md
+ */
+
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"google.golang.org/protobuf/proto"
+	"sort"
+)
+
+type statusJSON struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// MarshalJSON encodes status as {"code": "SUCCESS", "message": ""}
+func (t Status) MarshalJSON() ([]byte, error) {
+	return json.Marshal(statusJSON{Code: t.Code.String(), Message: t.Message})
+}
+
+func (t *Status) UnmarshalJSON(data []byte) error {
+	var sj statusJSON
+	if err := json.Unmarshal(data, &sj); err != nil {
+		return err
+	}
+	code, ok := ultipa.ErrorCode_value[sj.Code]
+	if !ok {
+		return errors.New(fmt.Sprintf("unknown status code %s", sj.Code))
+	}
+	t.Code = ultipa.ErrorCode(code)
+	t.Message = sj.Message
+	return nil
+}
+
+type dataItemJSON struct {
+	Alias   string            `json:"alias"`
+	Type    string            `json:"type"`
+	Nodes   []*structs.Node   `json:"nodes,omitempty"`
+	Edges   []*structs.Edge   `json:"edges,omitempty"`
+	Paths   []*structs.Path   `json:"paths,omitempty"`
+	Schemas []*structs.Schema `json:"schemas,omitempty"`
+	Table   *structs.Table    `json:"table,omitempty"`
+	Attr    *structs.Attr     `json:"attr,omitempty"`
+}
+
+// MarshalJSON encodes the data item by its type, e.g. {"alias": "n", "type": "node", "nodes": [...], "schemas": [...]}
+func (di *DataItem) MarshalJSON() ([]byte, error) {
+	item := map[string]interface{}{
+		"alias": di.Alias,
+		"type":  structs.GetJSONResultType(di.Type),
+	}
+
+	switch di.Type {
+	case ultipa.ResultType_RESULT_TYPE_NODE:
+		nodes, schemas, err := di.AsNodes()
+		if err != nil {
+			return nil, err
+		}
+		if nodes == nil {
+			nodes = []*structs.Node{}
+		}
+		item["nodes"] = nodes
+		item["schemas"] = structs.SortedSchemas(schemas)
+	case ultipa.ResultType_RESULT_TYPE_EDGE:
+		edges, schemas, err := di.AsEdges()
+		if err != nil {
+			return nil, err
+		}
+		if edges == nil {
+			edges = []*structs.Edge{}
+		}
+		item["edges"] = edges
+		item["schemas"] = structs.SortedSchemas(schemas)
+	case ultipa.ResultType_RESULT_TYPE_PATH:
+		paths, err := di.AsPaths()
+		if err != nil {
+			return nil, err
+		}
+		if paths == nil {
+			paths = []*structs.Path{}
+		}
+		item["paths"] = paths
+	case ultipa.ResultType_RESULT_TYPE_TABLE:
+		table, err := di.AsTable()
+		if err != nil {
+			return nil, err
+		}
+		item["table"] = table
+	case ultipa.ResultType_RESULT_TYPE_ATTR:
+		// the attr before ListAttrAsAttr, so a LIST attr keeps one value per row
+		var attr *structs.Attr
+		if attrAlias, ok := di.Data.(*ultipa.AttrAlias); ok && attrAlias != nil && attrAlias.Attr != nil {
+			var err error
+			attr, err = parseAttr(attrAlias.Attr, di.Alias)
+			if err != nil {
+				return nil, err
+			}
+		}
+		item["attr"] = attr
+	}
+
+	return json.Marshal(item)
+}
+
+// UnmarshalJSON decodes the data item and rebuilds its Data, so AsNodes, AsTable, AsAttr etc. work as on a server reply
+func (di *DataItem) UnmarshalJSON(data []byte) error {
+	var dj dataItemJSON
+	if err := json.Unmarshal(data, &dj); err != nil {
+		return err
+	}
+	resultType, err := structs.ParseJSONResultType(dj.Type)
+	if err != nil {
+		return err
+	}
+	di.Alias = dj.Alias
+	di.Type = resultType
+	di.Data = nil
+
+	switch resultType {
+	case ultipa.ResultType_RESULT_TYPE_NODE:
+		table, err := nodesToEntityTable(dj.Nodes, dj.Schemas)
+		if err != nil {
+			return errors.New(fmt.Sprintf("dataItem %s: %v", dj.Alias, err))
+		}
+		di.Data = &ultipa.NodeAlias{Alias: dj.Alias, NodeTable: table}
+	case ultipa.ResultType_RESULT_TYPE_EDGE:
+		table, err := edgesToEntityTable(dj.Edges, dj.Schemas)
+		if err != nil {
+			return errors.New(fmt.Sprintf("dataItem %s: %v", dj.Alias, err))
+		}
+		di.Data = &ultipa.EdgeAlias{Alias: dj.Alias, EdgeTable: table}
+	case ultipa.ResultType_RESULT_TYPE_PATH:
+		paths, err := pathsToProto(dj.Paths)
+		if err != nil {
+			return errors.New(fmt.Sprintf("dataItem %s: %v", dj.Alias, err))
+		}
+		di.Data = &ultipa.PathAlias{Alias: dj.Alias, Paths: paths}
+	case ultipa.ResultType_RESULT_TYPE_TABLE:
+		if dj.Table == nil {
+			return nil
+		}
+		table, err := tableToProto(dj.Table)
+		if err != nil {
+			return errors.New(fmt.Sprintf("dataItem %s: %v", dj.Alias, err))
+		}
+		if table.TableName == "" {
+			table.TableName = dj.Alias
+		}
+		di.Data = table
+	case ultipa.ResultType_RESULT_TYPE_ATTR:
+		if dj.Attr == nil {
+			return nil
+		}
+		attr, err := attrToProto(dj.Attr)
+		if err != nil {
+			return errors.New(fmt.Sprintf("dataItem %s: %v", dj.Alias, err))
+		}
+		di.Data = &ultipa.AttrAlias{Alias: dj.Alias, Attr: attr}
+	}
+	return nil
+}
+
+type uqlResponseJSON struct {
+	Status    *Status              `json:"status"`
+	Statistic *Statistic           `json:"statistic"`
+	Aliases   []string             `json:"aliases"`
+	Results   map[string]*DataItem `json:"results"`
+}
+
+// MarshalJSON encodes the response as {"status": {...}, "statistic": {...}, "aliases": [...], "results": {alias: dataItem}}
+func (r *UQLResponse) MarshalJSON() ([]byte, error) {
+	rj := uqlResponseJSON{
+		Status:    r.Status,
+		Statistic: r.Statistic,
+		Aliases:   []string{},
+		Results:   map[string]*DataItem{},
+	}
+	if rj.Status == nil {
+		rj.Status = &Status{}
+	}
+	if rj.Statistic == nil {
+		rj.Statistic = &Statistic{}
+	}
+	for _, alias := range r.AliasList {
+		item := r.Alias(alias)
+		item.Alias = alias
+		rj.Aliases = append(rj.Aliases, alias)
+		rj.Results[alias] = item
+	}
+	return json.Marshal(rj)
+}
+
+// UnmarshalJSON decodes the response and rebuilds Reply, so Alias and Get return data items as on a server reply
+func (r *UQLResponse) UnmarshalJSON(data []byte) error {
+	var rj uqlResponseJSON
+	if err := json.Unmarshal(data, &rj); err != nil {
+		return err
+	}
+
+	r.Status = rj.Status
+	if r.Status == nil {
+		r.Status = &Status{}
+	}
+	r.Statistic = rj.Statistic
+	if r.Statistic == nil {
+		r.Statistic = &Statistic{}
+	}
+	r.AliasList = rj.Aliases
+	r.DataItemMap = map[string]struct {
+		DataItem *DataItem
+		Index    int
+	}{}
+	r.Reply = &ultipa.UqlReply{
+		Status:         &ultipa.Status{ErrorCode: r.Status.Code, Msg: r.Status.Message},
+		TotalTimeCost:  int32(r.Statistic.TotalCost),
+		EngineTimeCost: int32(r.Statistic.EngineCost),
+	}
+
+	for _, alias := range rj.Aliases {
+		item := rj.Results[alias]
+		if item == nil {
+			return errors.New(fmt.Sprintf("result of alias %s is not found", alias))
+		}
+		r.Reply.Alias = append(r.Reply.Alias, &ultipa.ResultAlias{Alias: alias, ResultType: item.Type})
+		switch v := item.Data.(type) {
+		case *ultipa.NodeAlias:
+			r.Reply.Nodes = append(r.Reply.Nodes, v)
+		case *ultipa.EdgeAlias:
+			r.Reply.Edges = append(r.Reply.Edges, v)
+		case *ultipa.PathAlias:
+			r.Reply.Paths = append(r.Reply.Paths, v)
+		case *ultipa.Table:
+			r.Reply.Tables = append(r.Reply.Tables, v)
+		case *ultipa.AttrAlias:
+			r.Reply.Attrs = append(r.Reply.Attrs, v)
+		}
+	}
+	return nil
+}
+
+func schemaToProto(schema *structs.Schema) *ultipa.Schema {
+	oSchema := &ultipa.Schema{SchemaName: schema.Name}
+	for _, prop := range schema.Properties {
+		oSchema.Properties = append(oSchema.Properties, &ultipa.Property{
+			PropertyName: prop.Name,
+			PropertyType: prop.Type,
+			SubTypes:     prop.SubTypes,
+		})
+	}
+	return oSchema
+}
+
+// entitySchemas returns the schemas of entities by name, a schema missing in json is inferred from the values of its entities
+func entitySchemas(schemas []*structs.Schema, schemaNames []string, values []*structs.Values) (map[string]*structs.Schema, []*structs.Schema) {
+	schemaMap := map[string]*structs.Schema{}
+	ordered := []*structs.Schema{}
+	for _, schema := range schemas {
+		schemaMap[schema.Name] = schema
+		ordered = append(ordered, schema)
+	}
+
+	inferred := map[string]*structs.Schema{}
+	for i, name := range schemaNames {
+		if _, ok := schemaMap[name]; ok && inferred[name] == nil {
+			continue
+		}
+		schema := inferred[name]
+		if schema == nil {
+			schema = structs.NewSchema(name)
+			inferred[name] = schema
+			schemaMap[name] = schema
+			ordered = append(ordered, schema)
+		}
+		if values[i] == nil {
+			continue
+		}
+		keys := make([]string, 0, len(values[i].Data))
+		for key := range values[i].Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			t, subTypes := utils.InferJSONValueType(values[i].Data[key])
+			prop := schema.GetProperty(key)
+			if prop == nil {
+				schema.Properties = append(schema.Properties, &structs.Property{Name: key, Type: t, SubTypes: subTypes})
+			} else if prop.Type == ultipa.PropertyType_NULL_ {
+				prop.Type, prop.SubTypes = t, subTypes
+			}
+		}
+	}
+	return schemaMap, ordered
+}
+
+// jsonValueToBytes serializes a value decoded from json, BLOB values are decoded as []byte already
+func jsonValueToBytes(value interface{}, t ultipa.PropertyType, subTypes []ultipa.PropertyType) ([]byte, error) {
+	if v, ok := value.([]byte); ok && t == ultipa.PropertyType_BLOB {
+		return v, nil
+	}
+	return utils.ConvertInterfaceToBytesSafe(value, t, subTypes, nil)
+}
+
+// valuesToBytes serializes values in the order of schema properties
+func valuesToBytes(values *structs.Values, schema *structs.Schema) ([][]byte, error) {
+	var result [][]byte
+	for _, prop := range schema.Properties {
+		var value interface{}
+		if values != nil {
+			value = values.Get(prop.Name)
+		}
+		typed, err := utils.JSONValueAsInterface(value, prop.Type, prop.SubTypes)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("property %s of schema %s: %v", prop.Name, schema.Name, err))
+		}
+		bs, err := jsonValueToBytes(typed, prop.Type, prop.SubTypes)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("property %s of schema %s: %v", prop.Name, schema.Name, err))
+		}
+		result = append(result, bs)
+	}
+	return result, nil
+}
+
+func nodesToEntityTable(nodes []*structs.Node, schemas []*structs.Schema) (*ultipa.EntityTable, error) {
+	var names []string
+	var values []*structs.Values
+	for _, node := range nodes {
+		names = append(names, node.Schema)
+		values = append(values, node.Values)
+	}
+	schemaMap, ordered := entitySchemas(schemas, names, values)
+
+	table := &ultipa.EntityTable{}
+	for _, schema := range ordered {
+		table.Schemas = append(table.Schemas, schemaToProto(schema))
+	}
+	for _, node := range nodes {
+		bs, err := valuesToBytes(node.Values, schemaMap[node.Schema])
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("node %s: %v", node.ID, err))
+		}
+		table.EntityRows = append(table.EntityRows, &ultipa.EntityRow{
+			Id:         node.ID,
+			Uuid:       node.UUID,
+			SchemaName: node.Schema,
+			Values:     bs,
+		})
+	}
+	return table, nil
+}
+
+func edgesToEntityTable(edges []*structs.Edge, schemas []*structs.Schema) (*ultipa.EntityTable, error) {
+	var names []string
+	var values []*structs.Values
+	for _, edge := range edges {
+		names = append(names, edge.Schema)
+		values = append(values, edge.Values)
+	}
+	schemaMap, ordered := entitySchemas(schemas, names, values)
+
+	table := &ultipa.EntityTable{}
+	for _, schema := range ordered {
+		table.Schemas = append(table.Schemas, schemaToProto(schema))
+	}
+	for _, edge := range edges {
+		bs, err := valuesToBytes(edge.Values, schemaMap[edge.Schema])
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("edge %d: %v", edge.UUID, err))
+		}
+		table.EntityRows = append(table.EntityRows, &ultipa.EntityRow{
+			Uuid:       edge.UUID,
+			FromId:     edge.From,
+			FromUuid:   edge.FromUUID,
+			ToId:       edge.To,
+			ToUuid:     edge.ToUUID,
+			SchemaName: edge.Schema,
+			Values:     bs,
+		})
+	}
+	return table, nil
+}
+
+func pathsToProto(paths []*structs.Path) ([]*ultipa.Path, error) {
+	var oPaths []*ultipa.Path
+	for _, path := range paths {
+		nodeTable, err := nodesToEntityTable(path.Nodes, structs.SortedSchemas(path.NodeSchemas))
+		if err != nil {
+			return nil, err
+		}
+		edgeTable, err := edgesToEntityTable(path.Edges, structs.SortedSchemas(path.EdgeSchemas))
+		if err != nil {
+			return nil, err
+		}
+		oPaths = append(oPaths, &ultipa.Path{NodeTable: nodeTable, EdgeTable: edgeTable})
+	}
+	return oPaths, nil
+}
+
+func tableToProto(table *structs.Table) (*ultipa.Table, error) {
+	oTable := &ultipa.Table{TableName: table.Name}
+	for _, header := range table.Headers {
+		oTable.Headers = append(oTable.Headers, &ultipa.Header{PropertyName: header.Name, PropertyType: header.Type})
+	}
+	for _, row := range table.Rows {
+		oRow := &ultipa.TableRow{}
+		for index, value := range *row {
+			header := table.Headers[index]
+			bs, err := jsonValueToBytes(value, header.Type, header.SubTypes)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("table %s column %s: %v", table.Name, header.Name, err))
+			}
+			oRow.Values = append(oRow.Values, bs)
+		}
+		oTable.TableRows = append(oTable.TableRows, oRow)
+	}
+	return oTable, nil
+}
+
+func attrToProto(attr *structs.Attr) (*ultipa.Attr, error) {
+	oAttr := &ultipa.Attr{ValueType: attr.PropertyType}
+	if attr.Rows == nil {
+		return oAttr, nil
+	}
+	oAttr.Values = [][]byte{}
+
+	for _, row := range attr.Rows {
+		if attr.PropertyType != ultipa.PropertyType_LIST {
+			bs, err := jsonValueToBytes(row, attr.PropertyType, nil)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("attr %s: %v", attr.Name, err))
+			}
+			oAttr.Values = append(oAttr.Values, bs)
+			continue
+		}
+
+		oListData := &ultipa.AttrListData{Type: attr.ResultType}
+		switch r := row.(type) {
+		case nil:
+			oListData.IsNull = true
+		case *structs.AttrListData:
+			var err error
+			switch r.ResultType {
+			case ultipa.ResultType_RESULT_TYPE_NODE:
+				oListData.Nodes, err = nodesToEntityTable(r.Nodes, nil)
+			case ultipa.ResultType_RESULT_TYPE_EDGE:
+				oListData.Edges, err = edgesToEntityTable(r.Edges, nil)
+			case ultipa.ResultType_RESULT_TYPE_PATH:
+				oListData.Paths, err = pathsToProto(r.Paths)
+			default:
+				err = errors.New(fmt.Sprintf("attr list of %s is not supported", r.ResultType))
+			}
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("attr %s: %v", attr.Name, err))
+			}
+		case structs.Row:
+			oListData.Type = ultipa.ResultType_RESULT_TYPE_ATTR
+			for _, element := range r {
+				t, _ := utils.InferJSONValueType(element)
+				if t == ultipa.PropertyType_LIST {
+					return nil, errors.New(fmt.Sprintf("attr %s: nested list is not supported yet", attr.Name))
+				}
+				bs, err := jsonValueToBytes(element, t, nil)
+				if err != nil {
+					return nil, errors.New(fmt.Sprintf("attr %s: %v", attr.Name, err))
+				}
+				oListData.Attrs = append(oListData.Attrs, &ultipa.Attr{ValueType: t, Values: [][]byte{bs}})
+			}
+		default:
+			return nil, errors.New(fmt.Sprintf("attr %s: unexpected LIST value %T", attr.Name, row))
+		}
+		bs, err := proto.Marshal(oListData)
+		if err != nil {
+			return nil, err
+		}
+		oAttr.Values = append(oAttr.Values, bs)
+	}
+	return oAttr, nil
+}
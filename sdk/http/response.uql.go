@@ -124,3 +124,37 @@ func (r *UQLResponse) GetSingleTable() (*structs.Table, error) {
 	}
 	return nil, nil
 }
+
+// RowCounts returns the number of nodes, edges, paths, table rows or attr values of each alias
+func (r *UQLResponse) RowCounts() map[string]int {
+	counts := map[string]int{}
+	addRowCounts(counts, r.Reply)
+	return counts
+}
+
+// addRowCounts adds the rows of each alias of reply to counts
+func addRowCounts(counts map[string]int, reply *ultipa.UqlReply) {
+	if reply == nil {
+		return
+	}
+	for _, alias := range reply.Alias {
+		if _, ok := counts[alias.GetAlias()]; !ok {
+			counts[alias.GetAlias()] = 0
+		}
+	}
+	for _, nodes := range reply.Nodes {
+		counts[nodes.GetAlias()] += len(nodes.GetNodeTable().GetEntityRows())
+	}
+	for _, edges := range reply.Edges {
+		counts[edges.GetAlias()] += len(edges.GetEdgeTable().GetEntityRows())
+	}
+	for _, paths := range reply.Paths {
+		counts[paths.GetAlias()] += len(paths.GetPaths())
+	}
+	for _, table := range reply.Tables {
+		counts[table.GetTableName()] += len(table.GetTableRows())
+	}
+	for _, attr := range reply.Attrs {
+		counts[attr.GetAlias()] += len(attr.GetAttr().GetValues())
+	}
+}
@@ -5,6 +5,7 @@
 package http
 
 import (
+	"context"
 	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
 	"io"
 )
@@ -19,13 +20,36 @@ type UQLResponseStream struct {
 	Statistic *Statistic
 	AliasList []string
 	Resp      ultipa.UltipaRpcs_UqlClient
+	// Cancel cancels the request of the stream, it is called when the stream ends or is closed
+	Cancel context.CancelFunc
+
+	summary *StreamSummary
+	onClose func(summary *StreamSummary)
+	closed  bool
+}
+
+// StreamSummary is what a uql stream received before it is drained or closed, check OnClose of UQLResponseStream and Rows
+type StreamSummary struct {
+	Status      *Status
+	Statistic   *Statistic
+	ExplainPlan *ExplainPlan
+	RowCounts   map[string]int // rows received by alias, chunks not received before closing are not counted
+	Err         error
+}
+
+func newStreamSummary() *StreamSummary {
+	return &StreamSummary{
+		Status:    &Status{},
+		RowCounts: map[string]int{},
+	}
 }
 
 func NewUQLResponseStream(resp ultipa.UltipaRpcs_UqlClient) (response *UQLResponseStream, err error) {
 
 	response = &UQLResponseStream{
-		Resp:   resp,
-		Status: &Status{},
+		Resp:    resp,
+		Status:  &Status{},
+		summary: newStreamSummary(),
 		DataItemMap: map[string]struct {
 			DataItem *DataItem
 			Index    int
@@ -37,7 +61,7 @@ func NewUQLResponseStream(resp ultipa.UltipaRpcs_UqlClient) (response *UQLRespon
 
 func (r *UQLResponseStream) Recv(fetch bool) (response *UQLResponse, err error) {
 	if !fetch {
-		return nil, r.Resp.CloseSend()
+		return nil, r.Close()
 	}
 	response = &UQLResponse{
 		Status: &Status{},
@@ -50,10 +74,11 @@ func (r *UQLResponseStream) Recv(fetch bool) (response *UQLResponse, err error)
 	record, err := r.Resp.Recv()
 
 	if err == io.EOF {
-		_ = r.Resp.CloseSend()
+		_ = r.Close()
 		return nil, io.EOF
 	} else if err != nil {
-		_ = r.Resp.CloseSend()
+		r.summary.Err = err
+		_ = r.Close()
 		return nil, err
 	}
 
@@ -74,6 +99,7 @@ func (r *UQLResponseStream) Recv(fetch bool) (response *UQLResponse, err error)
 	response.Reply = record
 	response.Status.Code = record.Status.ErrorCode
 	response.Status.Message = record.Status.Msg
+	r.summarize(response)
 
 	if response.Status.Code != ultipa.ErrorCode_SUCCESS {
 		return response, nil
@@ -94,5 +120,36 @@ func (r *UQLResponseStream) NeedRedirect() bool {
 }
 
 func (r *UQLResponseStream) Close() error {
-	return r.Resp.CloseSend()
+	err := r.Resp.CloseSend()
+	if r.Cancel != nil {
+		r.Cancel()
+	}
+	if !r.closed {
+		r.closed = true
+		if r.onClose != nil {
+			r.onClose(r.summary)
+		}
+	}
+	return err
+}
+
+// OnClose sets f to be called once when the stream is drained or closed, f is called at once if the stream is closed already
+func (r *UQLResponseStream) OnClose(f func(summary *StreamSummary)) {
+	r.onClose = f
+	if r.closed && f != nil {
+		f(r.summary)
+	}
+}
+
+// summarize keeps the statistic of the first chunk, the latest status and counts the rows of response
+func (r *UQLResponseStream) summarize(response *UQLResponse) {
+	if r.summary.Statistic == nil {
+		r.summary.Statistic = response.Statistic
+		r.summary.ExplainPlan = response.ExplainPlan
+	}
+	if r.summary.Status.Code == ultipa.ErrorCode_SUCCESS {
+		r.summary.Status.Code = response.Status.Code
+		r.summary.Status.Message = response.Status.Message
+	}
+	addRowCounts(r.summary.RowCounts, response.Reply)
 }
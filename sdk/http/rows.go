@@ -0,0 +1,429 @@
+/**
+ * Return a Rows cursor to decode UQL results one row at a time
+ */
+
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"io"
+	"reflect"
+)
+
+// Rows is a cursor over one alias of an uql stream, only the current chunk of the stream is kept in memory,
+// and the rows of the chunk are decoded one by one by Next.
+// Usage:
+//
+//	rows, err := api.Query("find().nodes() as n return n{*}", nil)
+//	defer rows.Close()
+//	for rows.Next() {
+//		var node structs.Node
+//		err = rows.Scan(&node)
+//	}
+//	err = rows.Err()
+type Rows struct {
+	Status      *Status
+	Statistic   *Statistic
+	ExplainPlan *ExplainPlan
+	AliasList   []string
+
+	stream     ultipa.UltipaRpcs_UqlClient
+	cancel     context.CancelFunc
+	aliasTypes map[string]ultipa.ResultType
+	alias      string
+	resultType ultipa.ResultType
+
+	reply    *ultipa.UqlReply
+	entities []*ultipa.EntityRow
+	paths    []*ultipa.Path
+	rows     []*ultipa.TableRow
+	attrRows structs.Row
+	index    int
+	schemas  map[string]*structs.Schema
+	headers  []*structs.Property
+
+	current   interface{}
+	started   bool
+	closed    bool
+	err       error
+	rowCounts map[string]int
+	onClose   func(summary *StreamSummary)
+}
+
+// NewRows receives the first chunk of resp to learn the status and aliases, cancel is called when Rows is closed
+func NewRows(resp ultipa.UltipaRpcs_UqlClient, cancel context.CancelFunc) (*Rows, error) {
+	rows := &Rows{
+		Status:     &Status{},
+		stream:     resp,
+		cancel:     cancel,
+		aliasTypes: map[string]ultipa.ResultType{},
+		schemas:    map[string]*structs.Schema{},
+		index:      -1,
+		rowCounts:  map[string]int{},
+	}
+
+	record, err := resp.Recv()
+	if err == io.EOF {
+		rows.Status.Code = ultipa.ErrorCode_SUCCESS
+		rows.Statistic, _ = ParseStatistic(nil)
+		rows.ExplainPlan, _ = ParseExplainPlan(nil)
+		_ = rows.Close()
+		return rows, nil
+	} else if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+
+	rows.Statistic, err = ParseStatistic(record.Statistics)
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	rows.ExplainPlan, err = ParseExplainPlan(record.ExplainPlan)
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+
+	rows.Status.Code = record.Status.ErrorCode
+	rows.Status.Message = record.Status.Msg
+	if rows.Status.Code != ultipa.ErrorCode_SUCCESS {
+		rows.err = errors.New(fmt.Sprintf("uql failed, code: %v, message: %s", rows.Status.Code, rows.Status.Message))
+		_ = rows.Close()
+		return rows, nil
+	}
+
+	for _, alias := range record.Alias {
+		rows.AliasList = append(rows.AliasList, alias.GetAlias())
+		rows.aliasTypes[alias.GetAlias()] = alias.GetResultType()
+	}
+	if len(rows.AliasList) > 0 {
+		rows.alias = rows.AliasList[0]
+		rows.resultType = rows.aliasTypes[rows.alias]
+	}
+	rows.reply = record
+	addRowCounts(rows.rowCounts, record)
+
+	return rows, nil
+}
+
+func (r *Rows) NeedRedirect() bool {
+	return r.Status.Code == ultipa.ErrorCode_RAFT_REDIRECT
+}
+
+// Select chooses the alias to iterate, the first alias is iterated by default, it must be called before Next
+func (r *Rows) Select(alias string) error {
+	if r.started {
+		return errors.New("Rows.Select must be called before Next")
+	}
+	t, ok := r.aliasTypes[alias]
+	if !ok {
+		return errors.New(fmt.Sprintf("alias %s is not found in the result, aliases: %v", alias, r.AliasList))
+	}
+	r.alias = alias
+	r.resultType = t
+	return nil
+}
+
+// Alias returns the alias being iterated
+func (r *Rows) Alias() string {
+	return r.alias
+}
+
+// Type returns the result type of the alias being iterated
+func (r *Rows) Type() ultipa.ResultType {
+	return r.resultType
+}
+
+// Schemas returns the schemas of the nodes or edges read so far
+func (r *Rows) Schemas() map[string]*structs.Schema {
+	return r.schemas
+}
+
+// Headers returns the headers of the table being iterated
+func (r *Rows) Headers() []*structs.Property {
+	return r.headers
+}
+
+// Next decodes the next row, it returns false when the stream is done, failed or closed, check Err for the failure
+func (r *Rows) Next() bool {
+	if r.closed || r.err != nil {
+		return false
+	}
+
+	if !r.started {
+		r.started = true
+		if err := r.loadChunk(); err != nil {
+			r.fail(err)
+			return false
+		}
+	}
+
+	for {
+		r.index++
+		if r.index < r.chunkSize() {
+			current, err := r.decode(r.index)
+			if err != nil {
+				r.fail(err)
+				return false
+			}
+			r.current = current
+			return true
+		}
+
+		if !r.recv() {
+			return false
+		}
+	}
+}
+
+// Value returns the current row, which is *structs.Node, *structs.Edge, *structs.Path, *structs.Row or an attr value
+func (r *Rows) Value() interface{} {
+	return r.current
+}
+
+// Scan copies the current row into dest:
+// nodes, edges and paths are scanned into *structs.Node, *structs.Edge, *structs.Path or their pointers;
+// nodes and edges are also scanned into a pointer to a user struct by `ultipa:"name"` tags, check ScanTag;
+// table rows are scanned into *structs.Row, a pointer to a user struct, or one dest per column;
+// attr values are scanned into a single dest.
+// *interface{} accepts any row or value
+func (r *Rows) Scan(dest ...interface{}) error {
+	if r.current == nil {
+		return errors.New("Rows.Scan called without a successful Next")
+	}
+
+	switch current := r.current.(type) {
+	case *structs.Row:
+		if len(dest) == 1 {
+			switch d := dest[0].(type) {
+			case *structs.Row:
+				*d = *current
+				return nil
+			case **structs.Row:
+				*d = current
+				return nil
+			case *interface{}:
+				*d = current
+				return nil
+			}
+			if isScanStruct(dest[0]) {
+				return scanRow(r.headers, current, reflect.ValueOf(dest[0]).Elem())
+			}
+		}
+		if len(dest) != len(*current) {
+			return errors.New(fmt.Sprintf("table %s has %d columns, but got %d destinations", r.alias, len(*current), len(dest)))
+		}
+		for i, value := range *current {
+			if err := scanValue(dest[i], value); err != nil {
+				return errors.New(fmt.Sprintf("column %d: %s", i, err.Error()))
+			}
+		}
+		return nil
+	default:
+		if len(dest) != 1 {
+			return errors.New(fmt.Sprintf("alias %s expects 1 destination, but got %d", r.alias, len(dest)))
+		}
+		return scanValue(dest[0], current)
+	}
+}
+
+// Err returns the error that stopped Next, it is nil when the stream is read to the end or closed by Close
+func (r *Rows) Err() error {
+	return r.err
+}
+
+// Close stops reading the stream and cancels the request, it is safe to be called more than once
+func (r *Rows) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	r.current = nil
+	r.reply = nil
+	r.entities, r.paths, r.rows, r.attrRows = nil, nil, nil, nil
+	var err error
+	if r.stream != nil {
+		err = r.stream.CloseSend()
+	}
+	if r.cancel != nil {
+		r.cancel()
+	}
+	if r.onClose != nil {
+		r.onClose(r.summary())
+	}
+	return err
+}
+
+// OnClose sets f to be called once when Rows is drained or closed, f is called at once if Rows is closed already
+func (r *Rows) OnClose(f func(summary *StreamSummary)) {
+	r.onClose = f
+	if r.closed && f != nil {
+		f(r.summary())
+	}
+}
+
+func (r *Rows) summary() *StreamSummary {
+	return &StreamSummary{
+		Status:      r.Status,
+		Statistic:   r.Statistic,
+		ExplainPlan: r.ExplainPlan,
+		RowCounts:   r.rowCounts,
+		Err:         r.err,
+	}
+}
+
+func (r *Rows) fail(err error) {
+	r.err = err
+	_ = r.Close()
+}
+
+// recv receives the next chunk of the stream, returns false when the stream ends or fails
+func (r *Rows) recv() bool {
+	record, err := r.stream.Recv()
+	if err == io.EOF {
+		_ = r.Close()
+		return false
+	} else if err != nil {
+		r.fail(err)
+		return false
+	}
+
+	if record.Status != nil && record.Status.ErrorCode != ultipa.ErrorCode_SUCCESS {
+		r.Status.Code = record.Status.ErrorCode
+		r.Status.Message = record.Status.Msg
+		r.fail(errors.New(fmt.Sprintf("uql failed, code: %v, message: %s", r.Status.Code, r.Status.Message)))
+		return false
+	}
+
+	r.reply = record
+	addRowCounts(r.rowCounts, record)
+	if err = r.loadChunk(); err != nil {
+		r.fail(err)
+		return false
+	}
+	return true
+}
+
+// loadChunk finds the data of the alias in the current chunk, schemas and headers are kept from the previous chunks if absent
+func (r *Rows) loadChunk() error {
+	r.index = -1
+	r.entities, r.paths, r.rows, r.attrRows = nil, nil, nil, nil
+
+	switch r.resultType {
+	case ultipa.ResultType_RESULT_TYPE_NODE:
+		for _, nodes := range r.reply.Nodes {
+			if nodes.Alias == r.alias && nodes.NodeTable != nil {
+				r.mergeSchemas(parseEntitySchemas(nodes.NodeTable, ultipa.DBType_DBNODE))
+				r.entities = nodes.NodeTable.EntityRows
+				break
+			}
+		}
+	case ultipa.ResultType_RESULT_TYPE_EDGE:
+		for _, edges := range r.reply.Edges {
+			if edges.Alias == r.alias && edges.EdgeTable != nil {
+				r.mergeSchemas(parseEntitySchemas(edges.EdgeTable, ultipa.DBType_DBEDGE))
+				r.entities = edges.EdgeTable.EntityRows
+				break
+			}
+		}
+	case ultipa.ResultType_RESULT_TYPE_PATH:
+		for _, paths := range r.reply.Paths {
+			if paths.Alias == r.alias {
+				r.paths = paths.Paths
+				break
+			}
+		}
+	case ultipa.ResultType_RESULT_TYPE_TABLE:
+		for _, table := range r.reply.Tables {
+			if table.TableName == r.alias {
+				if len(table.Headers) > 0 {
+					r.headers = parseTableHeaders(table)
+				}
+				r.rows = table.TableRows
+				break
+			}
+		}
+	case ultipa.ResultType_RESULT_TYPE_ATTR:
+		for _, attrAlias := range r.reply.Attrs {
+			if attrAlias.Alias == r.alias && attrAlias.Attr != nil {
+				attr, err := attrAliasToAttr(attrAlias, r.alias)
+				if err != nil {
+					return err
+				}
+				r.attrRows = attr.Rows
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Rows) mergeSchemas(schemas map[string]*structs.Schema) {
+	for name, schema := range schemas {
+		r.schemas[name] = schema
+	}
+}
+
+func (r *Rows) chunkSize() int {
+	switch r.resultType {
+	case ultipa.ResultType_RESULT_TYPE_NODE, ultipa.ResultType_RESULT_TYPE_EDGE:
+		return len(r.entities)
+	case ultipa.ResultType_RESULT_TYPE_PATH:
+		return len(r.paths)
+	case ultipa.ResultType_RESULT_TYPE_TABLE:
+		return len(r.rows)
+	case ultipa.ResultType_RESULT_TYPE_ATTR:
+		return len(r.attrRows)
+	}
+	return 0
+}
+
+func (r *Rows) decode(index int) (interface{}, error) {
+	switch r.resultType {
+	case ultipa.ResultType_RESULT_TYPE_NODE:
+		return entityRowToNode(r.entities[index], r.schemas, r.alias)
+	case ultipa.ResultType_RESULT_TYPE_EDGE:
+		return entityRowToEdge(r.entities[index], r.schemas, r.alias)
+	case ultipa.ResultType_RESULT_TYPE_PATH:
+		return parsePath(r.paths[index], r.alias)
+	case ultipa.ResultType_RESULT_TYPE_TABLE:
+		return tableRowToRow(r.rows[index], r.headers)
+	case ultipa.ResultType_RESULT_TYPE_ATTR:
+		return r.attrRows[index], nil
+	}
+	return nil, errors.New(fmt.Sprintf("alias %s has unsupported result type %v", r.alias, r.resultType))
+}
+
+// scanValue copies value into dest, which must be a non-nil pointer, check ConvertScanValue for the conversions
+func scanValue(dest interface{}, value interface{}) error {
+	if d, ok := dest.(*interface{}); ok {
+		*d = value
+		return nil
+	}
+
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return errors.New(fmt.Sprintf("destination must be a non-nil pointer, but got %T", dest))
+	}
+
+	switch v := value.(type) {
+	case *structs.Node:
+		if isScanStruct(dest) {
+			return scanNode(v, dv.Elem())
+		}
+	case *structs.Edge:
+		if isScanStruct(dest) {
+			return scanEdge(v, dv.Elem())
+		}
+	}
+
+	if err := ConvertScanValue(value, dv.Elem()); err != nil {
+		return errors.New(fmt.Sprintf("unable to scan %T into %T: %s", value, dest, err.Error()))
+	}
+	return nil
+}
@@ -8,10 +8,10 @@ import (
 
 // 存储返回的统计信息
 type Statistic struct {
-	NodeAffected int `key:"node_affected" type:"int"`
-	EdgeAffected int `key:"edge_affected" type:"int"`
-	TotalCost    int `key:"total_time_cost" type:"int"`
-	EngineCost   int `key:"engine_time_cost" type:"int"`
+	NodeAffected int `key:"node_affected" type:"int" json:"node_affected"`
+	EdgeAffected int `key:"edge_affected" type:"int" json:"edge_affected"`
+	TotalCost    int `key:"total_time_cost" type:"int" json:"total_time_cost"`
+	EngineCost   int `key:"engine_time_cost" type:"int" json:"engine_time_cost"`
 }
 
 func ParseStatistic(table *ultipa.Table) (*Statistic, error) {
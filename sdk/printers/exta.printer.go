@@ -0,0 +1,45 @@
+package printers
+
+import (
+	"github.com/alexeyco/simpletable"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+)
+
+func PrintExtaList(extas []*structs.Exta) {
+	table := simpletable.New()
+
+	table.Header.Cells = []*simpletable.Cell{
+		{
+			Text: "Exta Name",
+		},
+		{
+			Text: "Version",
+		},
+		{
+			Text: "Author",
+		},
+		{
+			Text: "Detail",
+		},
+	}
+
+	for _, exta := range extas {
+
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{
+				Text: exta.Name,
+			},
+			{
+				Text: exta.Version,
+			},
+			{
+				Text: exta.Author,
+			},
+			{
+				Text: exta.Detail,
+			},
+		})
+	}
+
+	table.Println()
+}
@@ -1,5 +1,11 @@
 package structs
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
 type Explain struct {
 	//Type        ultipa.PlanNodeType
 	Alias       string
@@ -7,3 +13,130 @@ type Explain struct {
 	Uql         string
 	Infos       string
 }
+
+// ExplainNode is a node of the explain plan tree
+type ExplainNode struct {
+	Alias    string
+	Uql      string
+	Infos    string
+	Children []*ExplainNode
+}
+
+// BuildExplainTree builds the trees from the pre-order explain list, where ChildrenNum tells how many of the following subtrees are children
+func BuildExplainTree(explains []*Explain) ([]*ExplainNode, error) {
+	var roots []*ExplainNode
+	for index := 0; index < len(explains); {
+		root, next, err := buildExplainNode(explains, index)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+		index = next
+	}
+	return roots, nil
+}
+
+// buildExplainNode builds the subtree starting at explains[index], returns the index after the subtree
+func buildExplainNode(explains []*Explain, index int) (*ExplainNode, int, error) {
+	explain := explains[index]
+	node := &ExplainNode{Alias: explain.Alias, Uql: explain.Uql, Infos: explain.Infos}
+	next := index + 1
+	for i := 0; i < int(explain.ChildrenNum); i++ {
+		if next >= len(explains) {
+			return nil, next, errors.New(fmt.Sprintf("explain plan %d (%s) has %d children, but only %d found", index, explain.Alias, explain.ChildrenNum, i))
+		}
+		child, end, err := buildExplainNode(explains, next)
+		if err != nil {
+			return nil, end, err
+		}
+		node.Children = append(node.Children, child)
+		next = end
+	}
+	return node, next, nil
+}
+
+// Walk visits the node and its descendants in pre-order, stops if cb returns an error
+func (node *ExplainNode) Walk(cb func(node *ExplainNode, depth int) error) error {
+	return node.walk(cb, 0)
+}
+
+func (node *ExplainNode) walk(cb func(node *ExplainNode, depth int) error, depth int) error {
+	if err := cb(node, depth); err != nil {
+		return err
+	}
+	for _, child := range node.Children {
+		if err := child.walk(cb, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// labelLines returns the non-empty alias, uql and infos of the node
+func (node *ExplainNode) labelLines() []string {
+	var lines []string
+	for _, line := range []string{node.Alias, node.Uql, node.Infos} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// ExplainTreeToDOT renders the trees as a Graphviz digraph, edges point from parents to children
+func ExplainTreeToDOT(roots []*ExplainNode) string {
+	var builder strings.Builder
+	builder.WriteString("digraph explain {\n")
+	builder.WriteString("  node [shape=box];\n")
+	walkExplainTrees(roots, func(id int, node *ExplainNode, parent int) {
+		var lines []string
+		for _, line := range node.labelLines() {
+			line = strings.ReplaceAll(line, `\`, `\\`)
+			line = strings.ReplaceAll(line, `"`, `\"`)
+			line = strings.ReplaceAll(line, "\r", "")
+			line = strings.ReplaceAll(line, "\n", `\n`)
+			lines = append(lines, line)
+		}
+		builder.WriteString(fmt.Sprintf("  n%d [label=\"%s\"];\n", id, strings.Join(lines, `\n`)))
+		if parent >= 0 {
+			builder.WriteString(fmt.Sprintf("  n%d -> n%d;\n", parent, id))
+		}
+	})
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// ExplainTreeToMermaid renders the trees as a Mermaid flowchart, edges point from parents to children
+func ExplainTreeToMermaid(roots []*ExplainNode) string {
+	var builder strings.Builder
+	builder.WriteString("flowchart TD\n")
+	replacer := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\r", "", "\n", "<br/>")
+	walkExplainTrees(roots, func(id int, node *ExplainNode, parent int) {
+		var lines []string
+		for _, line := range node.labelLines() {
+			lines = append(lines, replacer.Replace(line))
+		}
+		builder.WriteString(fmt.Sprintf("  n%d[\"%s\"]\n", id, strings.Join(lines, "<br/>")))
+		if parent >= 0 {
+			builder.WriteString(fmt.Sprintf("  n%d --> n%d\n", parent, id))
+		}
+	})
+	return builder.String()
+}
+
+// walkExplainTrees numbers the nodes in pre-order, parent is -1 for roots
+func walkExplainTrees(roots []*ExplainNode, cb func(id int, node *ExplainNode, parent int)) {
+	id := 0
+	var visit func(node *ExplainNode, parent int)
+	visit = func(node *ExplainNode, parent int) {
+		current := id
+		id++
+		cb(current, node, parent)
+		for _, child := range node.Children {
+			visit(child, current)
+		}
+	}
+	for _, root := range roots {
+		visit(root, -1)
+	}
+}
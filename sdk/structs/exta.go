@@ -0,0 +1,9 @@
+package structs
+
+// Exta is an installed extension listed by show().exta()
+type Exta struct {
+	Name    string
+	Version string
+	Author  string
+	Detail  string
+}
@@ -44,3 +44,13 @@ func DBTypeToString(dbType ultipa.DBType) string {
 //func (db ultipa.DBType) ToString(){
 //
 //}
+
+// GraphStats totals of a graph and of each of its schemas
+type GraphStats struct {
+	Name        string
+	Status      string
+	TotalNodes  int64
+	TotalEdges  int64
+	NodeSchemas map[string]int // schema name => total nodes
+	EdgeSchemas map[string]int // schema name => total edges
+}
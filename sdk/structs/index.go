@@ -7,9 +7,22 @@
 
 package structs
 
+import "strings"
+
 type Index struct {
 	Name       string
 	Properties string
 	Schema     string
 	Status     string
 }
+
+// IsDone returns true if the index is built
+func (index *Index) IsDone() bool {
+	return strings.EqualFold(strings.TrimSpace(index.Status), "done")
+}
+
+// IsFailed returns true if the index failed to build
+func (index *Index) IsFailed() bool {
+	status := strings.ToLower(index.Status)
+	return strings.Contains(status, "fail") || strings.Contains(status, "error")
+}
@@ -0,0 +1,529 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/types"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The json encoding of result types, see doc/07.json.md for the schema.
+// uuids are encoded as strings, values are encoded by the go types of ConvertBytesToInterface:
+// UltipaTime as a RFC3339 string, types.Point as {"latitude","longitude"}, BLOB as a base64 string, nil as null.
+
+// jsonUUID encodes an uuid as a string, which keeps the precision in javascript
+type jsonUUID types.UUID
+
+func (u jsonUUID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(uint64(u), 10))
+}
+
+func (u *jsonUUID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	str := strings.Trim(string(data), `"`)
+	uuid, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return errors.New(fmt.Sprintf("%s is not a valid uuid", string(data)))
+	}
+	*u = jsonUUID(uuid)
+	return nil
+}
+
+// GetJSONType returns the type name of a PropertyType used in json, e.g. string, int32, string[] for LIST<string>
+func GetJSONType(t ultipa.PropertyType, subTypes []ultipa.PropertyType) string {
+	if t == ultipa.PropertyType_LIST && len(subTypes) > 0 {
+		return GetJSONType(subTypes[0], nil) + "[]"
+	}
+	if name, ok := PropertyReverseMap[t]; ok {
+		return name
+	}
+	return strings.ToLower(t.String())
+}
+
+// ParseJSONType parses the type name returned by GetJSONType
+func ParseJSONType(s string) (ultipa.PropertyType, []ultipa.PropertyType, error) {
+	if strings.HasSuffix(s, "[]") {
+		subType, _, err := ParseJSONType(strings.TrimSuffix(s, "[]"))
+		if err != nil {
+			return 0, nil, err
+		}
+		return ultipa.PropertyType_LIST, []ultipa.PropertyType{subType}, nil
+	}
+	if t, ok := PropertyMap[s]; ok {
+		return t, nil, nil
+	}
+	if t, ok := ultipa.PropertyType_value[strings.ToUpper(s)]; ok {
+		return ultipa.PropertyType(t), nil, nil
+	}
+	return 0, nil, errors.New(fmt.Sprintf("unknown property type %s", s))
+}
+
+// GetJSONResultType returns the result type name used in json, e.g. node, edge, path, table, attr
+func GetJSONResultType(t ultipa.ResultType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "RESULT_TYPE_"))
+}
+
+// ParseJSONResultType parses the result type name returned by GetJSONResultType
+func ParseJSONResultType(s string) (ultipa.ResultType, error) {
+	if t, ok := ultipa.ResultType_value["RESULT_TYPE_"+strings.ToUpper(s)]; ok {
+		return ultipa.ResultType(t), nil
+	}
+	return ultipa.ResultType_RESULT_TYPE_UNSET, errors.New(fmt.Sprintf("unknown result type %s", s))
+}
+
+// MarshalJSON encodes values as an object, keys are sorted
+func (v Values) MarshalJSON() ([]byte, error) {
+	if v.Data == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(v.Data)
+}
+
+// UnmarshalJSON decodes values without schema, numbers become int64 or float64, use ConvertJSONValues to restore the property types
+func (v *Values) UnmarshalJSON(data []byte) error {
+	value, err := utils.DecodeJSONValue(data)
+	if err != nil {
+		return err
+	}
+	v.Data = map[string]interface{}{}
+	if value == nil {
+		return nil
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return errors.New(fmt.Sprintf("values should be a json object, but got %T", value))
+	}
+	for key, element := range m {
+		v.Data[key] = element
+	}
+	return nil
+}
+
+// ConvertJSONValues converts the values decoded from json to the types of properties
+func (v *Values) ConvertJSONValues(properties []*Property) error {
+	for _, prop := range properties {
+		value, ok := v.Data[prop.Name]
+		if !ok {
+			continue
+		}
+		converted, err := utils.JSONValueAsInterface(value, prop.Type, prop.SubTypes)
+		if err != nil {
+			return errors.New(fmt.Sprintf("property %s: %v", prop.Name, err))
+		}
+		v.Data[prop.Name] = converted
+	}
+	return nil
+}
+
+type propertyJSON struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Desc   string `json:"desc,omitempty"`
+	Lte    bool   `json:"lte,omitempty"`
+	Schema string `json:"schema,omitempty"`
+}
+
+func (p Property) MarshalJSON() ([]byte, error) {
+	return json.Marshal(propertyJSON{
+		Name:   p.Name,
+		Type:   GetJSONType(p.Type, p.SubTypes),
+		Desc:   p.Desc,
+		Lte:    p.Lte,
+		Schema: p.Schema,
+	})
+}
+
+func (p *Property) UnmarshalJSON(data []byte) error {
+	var pj propertyJSON
+	if err := json.Unmarshal(data, &pj); err != nil {
+		return err
+	}
+	t, subTypes, err := ParseJSONType(pj.Type)
+	if err != nil {
+		return err
+	}
+	*p = Property{Name: pj.Name, Desc: pj.Desc, Lte: pj.Lte, Schema: pj.Schema, Type: t, SubTypes: subTypes}
+	return nil
+}
+
+type schemaJSON struct {
+	Name       string      `json:"name"`
+	Type       string      `json:"type"`
+	Desc       string      `json:"desc,omitempty"`
+	Total      int         `json:"total,omitempty"`
+	Properties []*Property `json:"properties"`
+}
+
+func (s Schema) MarshalJSON() ([]byte, error) {
+	properties := s.Properties
+	if properties == nil {
+		properties = []*Property{}
+	}
+	return json.Marshal(schemaJSON{
+		Name:       s.Name,
+		Type:       strings.ToLower(strings.TrimPrefix(s.DBType.String(), "DB")),
+		Desc:       s.Desc,
+		Total:      s.Total,
+		Properties: properties,
+	})
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var sj schemaJSON
+	if err := json.Unmarshal(data, &sj); err != nil {
+		return err
+	}
+	dbType, ok := ultipa.DBType_value["DB"+strings.ToUpper(sj.Type)]
+	if !ok {
+		return errors.New(fmt.Sprintf("unknown schema type %s of schema %s", sj.Type, sj.Name))
+	}
+	*s = Schema{Name: sj.Name, Type: sj.Type, DBType: ultipa.DBType(dbType), Desc: sj.Desc, Total: sj.Total, Properties: sj.Properties}
+	if s.Properties == nil {
+		s.Properties = []*Property{}
+	}
+	return nil
+}
+
+// SortedSchemas returns the schemas of map sorted by name
+func SortedSchemas(schemas map[string]*Schema) []*Schema {
+	result := []*Schema{}
+	for _, schema := range schemas {
+		result = append(result, schema)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+type nodeJSON struct {
+	Name   string   `json:"name,omitempty"`
+	ID     string   `json:"id"`
+	UUID   jsonUUID `json:"uuid"`
+	Schema string   `json:"schema"`
+	Values *Values  `json:"values"`
+}
+
+func (node Node) MarshalJSON() ([]byte, error) {
+	values := node.Values
+	if values == nil {
+		values = NewValues()
+	}
+	return json.Marshal(nodeJSON{Name: node.Name, ID: node.ID, UUID: jsonUUID(node.UUID), Schema: node.Schema, Values: values})
+}
+
+// UnmarshalJSON decodes a node, values are not typed by schema, see Values.UnmarshalJSON
+func (node *Node) UnmarshalJSON(data []byte) error {
+	var nj nodeJSON
+	if err := json.Unmarshal(data, &nj); err != nil {
+		return err
+	}
+	if nj.Values == nil {
+		nj.Values = NewValues()
+	}
+	*node = Node{Name: nj.Name, ID: nj.ID, UUID: types.UUID(nj.UUID), Schema: nj.Schema, Values: nj.Values}
+	return nil
+}
+
+type edgeJSON struct {
+	Name     string   `json:"name,omitempty"`
+	UUID     jsonUUID `json:"uuid"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	FromUUID jsonUUID `json:"from_uuid"`
+	ToUUID   jsonUUID `json:"to_uuid"`
+	Schema   string   `json:"schema"`
+	Values   *Values  `json:"values"`
+}
+
+func (edge Edge) MarshalJSON() ([]byte, error) {
+	values := edge.Values
+	if values == nil {
+		values = NewValues()
+	}
+	return json.Marshal(edgeJSON{
+		Name:     edge.Name,
+		UUID:     jsonUUID(edge.UUID),
+		From:     edge.From,
+		To:       edge.To,
+		FromUUID: jsonUUID(edge.FromUUID),
+		ToUUID:   jsonUUID(edge.ToUUID),
+		Schema:   edge.Schema,
+		Values:   values,
+	})
+}
+
+// UnmarshalJSON decodes an edge, values are not typed by schema, see Values.UnmarshalJSON
+func (edge *Edge) UnmarshalJSON(data []byte) error {
+	var ej edgeJSON
+	if err := json.Unmarshal(data, &ej); err != nil {
+		return err
+	}
+	if ej.Values == nil {
+		ej.Values = NewValues()
+	}
+	*edge = Edge{
+		Name:     ej.Name,
+		UUID:     types.UUID(ej.UUID),
+		From:     ej.From,
+		To:       ej.To,
+		FromUUID: types.UUID(ej.FromUUID),
+		ToUUID:   types.UUID(ej.ToUUID),
+		Schema:   ej.Schema,
+		Values:   ej.Values,
+	}
+	return nil
+}
+
+type pathJSON struct {
+	Name        string    `json:"name,omitempty"`
+	Nodes       []*Node   `json:"nodes"`
+	Edges       []*Edge   `json:"edges"`
+	NodeSchemas []*Schema `json:"node_schemas"`
+	EdgeSchemas []*Schema `json:"edge_schemas"`
+}
+
+func (p Path) MarshalJSON() ([]byte, error) {
+	pj := pathJSON{
+		Name:        p.Name,
+		Nodes:       p.Nodes,
+		Edges:       p.Edges,
+		NodeSchemas: SortedSchemas(p.NodeSchemas),
+		EdgeSchemas: SortedSchemas(p.EdgeSchemas),
+	}
+	if pj.Nodes == nil {
+		pj.Nodes = []*Node{}
+	}
+	if pj.Edges == nil {
+		pj.Edges = []*Edge{}
+	}
+	return json.Marshal(pj)
+}
+
+// UnmarshalJSON decodes a path, values of nodes and edges are typed by node_schemas and edge_schemas
+func (p *Path) UnmarshalJSON(data []byte) error {
+	var pj pathJSON
+	if err := json.Unmarshal(data, &pj); err != nil {
+		return err
+	}
+	*p = *NewPath()
+	p.Name = pj.Name
+	p.Nodes = pj.Nodes
+	p.Edges = pj.Edges
+	for _, schema := range pj.NodeSchemas {
+		p.NodeSchemas[schema.Name] = schema
+	}
+	for _, schema := range pj.EdgeSchemas {
+		p.EdgeSchemas[schema.Name] = schema
+	}
+	for _, node := range p.Nodes {
+		if schema := p.NodeSchemas[node.Schema]; schema != nil {
+			if err := node.Values.ConvertJSONValues(schema.Properties); err != nil {
+				return errors.New(fmt.Sprintf("node %s: %v", node.ID, err))
+			}
+		}
+	}
+	for _, edge := range p.Edges {
+		if schema := p.EdgeSchemas[edge.Schema]; schema != nil {
+			if err := edge.Values.ConvertJSONValues(schema.Properties); err != nil {
+				return errors.New(fmt.Sprintf("edge %d: %v", edge.UUID, err))
+			}
+		}
+	}
+	return nil
+}
+
+type tableJSON struct {
+	Name    string              `json:"name"`
+	Headers []*Property         `json:"headers"`
+	Rows    [][]json.RawMessage `json:"rows"`
+}
+
+func (t Table) MarshalJSON() ([]byte, error) {
+	headers := t.Headers
+	if headers == nil {
+		headers = []*Property{}
+	}
+	rows := t.Rows
+	if rows == nil {
+		rows = []*Row{}
+	}
+	return json.Marshal(struct {
+		Name    string      `json:"name"`
+		Headers []*Property `json:"headers"`
+		Rows    []*Row      `json:"rows"`
+	}{t.Name, headers, rows})
+}
+
+// UnmarshalJSON decodes a table, values of rows are typed by headers
+func (t *Table) UnmarshalJSON(data []byte) error {
+	var tj tableJSON
+	if err := json.Unmarshal(data, &tj); err != nil {
+		return err
+	}
+	*t = *NewTable()
+	t.Name = tj.Name
+	if tj.Headers != nil {
+		t.Headers = tj.Headers
+	}
+	for _, rawRow := range tj.Rows {
+		if len(rawRow) > len(t.Headers) {
+			return errors.New(fmt.Sprintf("table %s has %d headers, but got %d values", t.Name, len(t.Headers), len(rawRow)))
+		}
+		row := Row{}
+		for index, raw := range rawRow {
+			value, err := utils.DecodeJSONValue(raw)
+			if err != nil {
+				return err
+			}
+			header := t.Headers[index]
+			value, err = utils.JSONValueAsInterface(value, header.Type, header.SubTypes)
+			if err != nil {
+				return errors.New(fmt.Sprintf("table %s column %s: %v", t.Name, header.Name, err))
+			}
+			row = append(row, value)
+		}
+		t.Rows = append(t.Rows, &row)
+	}
+	return nil
+}
+
+type attrJSON struct {
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`
+	ResultType string            `json:"result_type"`
+	Values     []json.RawMessage `json:"values"`
+}
+
+// MarshalJSON encodes an attr, each value of a LIST attr is an array of values, nodes, edges or paths according to result_type
+func (attr Attr) MarshalJSON() ([]byte, error) {
+	resultType := attr.ResultType
+	if resultType == ultipa.ResultType_RESULT_TYPE_UNSET {
+		resultType = ultipa.ResultType_RESULT_TYPE_ATTR
+	}
+	var values []interface{}
+	if attr.Rows != nil {
+		values = []interface{}{}
+	}
+	for _, row := range attr.Rows {
+		// rows of the attr returned by ListAttrAsAttr
+		switch r := row.(type) {
+		case *AttrNodes:
+			for _, nodes := range r.NodesList {
+				values = append(values, nodes)
+			}
+		case *AttrEdges:
+			for _, edges := range r.EdgesList {
+				values = append(values, edges)
+			}
+		case *AttrPaths:
+			for _, paths := range r.PathsList {
+				values = append(values, paths)
+			}
+		default:
+			values = append(values, row)
+		}
+	}
+	return json.Marshal(struct {
+		Name       string        `json:"name"`
+		Type       string        `json:"type"`
+		ResultType string        `json:"result_type"`
+		Values     []interface{} `json:"values"`
+	}{attr.Name, GetJSONType(attr.PropertyType, nil), GetJSONResultType(resultType), values})
+}
+
+// UnmarshalJSON decodes an attr, LIST values are decoded as AttrListData of nodes, edges and paths, or as Row of values
+func (attr *Attr) UnmarshalJSON(data []byte) error {
+	var aj attrJSON
+	if err := json.Unmarshal(data, &aj); err != nil {
+		return err
+	}
+	propertyType, subTypes, err := ParseJSONType(aj.Type)
+	if err != nil {
+		return err
+	}
+	resultType, err := ParseJSONResultType(aj.ResultType)
+	if err != nil {
+		return err
+	}
+
+	*attr = Attr{Name: aj.Name, PropertyType: propertyType, ResultType: resultType}
+	if aj.Values == nil {
+		return nil
+	}
+	attr.Rows = Row{}
+	for _, raw := range aj.Values {
+		if propertyType != ultipa.PropertyType_LIST {
+			value, err := utils.DecodeJSONValue(raw)
+			if err != nil {
+				return err
+			}
+			value, err = utils.JSONValueAsInterface(value, propertyType, subTypes)
+			if err != nil {
+				return errors.New(fmt.Sprintf("attr %s: %v", attr.Name, err))
+			}
+			attr.Rows = append(attr.Rows, value)
+			continue
+		}
+		if string(raw) == "null" {
+			attr.Rows = append(attr.Rows, nil)
+			continue
+		}
+		listData := NewAttrListData()
+		listData.ResultType = resultType
+		switch resultType {
+		case ultipa.ResultType_RESULT_TYPE_NODE:
+			err = json.Unmarshal(raw, &listData.Nodes)
+		case ultipa.ResultType_RESULT_TYPE_EDGE:
+			err = json.Unmarshal(raw, &listData.Edges)
+		case ultipa.ResultType_RESULT_TYPE_PATH:
+			err = json.Unmarshal(raw, &listData.Paths)
+		default:
+			var value interface{}
+			value, err = utils.DecodeJSONValue(raw)
+			if list, ok := value.([]interface{}); ok {
+				attr.Rows = append(attr.Rows, Row(list))
+				continue
+			}
+			if err == nil {
+				err = errors.New(fmt.Sprintf("value %s of LIST attr %s is not an array", string(raw), attr.Name))
+			}
+		}
+		if err != nil {
+			return err
+		}
+		attr.Rows = append(attr.Rows, listData)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the nodes, edges, paths or attrs of the list according to ResultType
+func (d AttrListData) MarshalJSON() ([]byte, error) {
+	switch d.ResultType {
+	case ultipa.ResultType_RESULT_TYPE_NODE:
+		if d.Nodes == nil {
+			return []byte("[]"), nil
+		}
+		return json.Marshal(d.Nodes)
+	case ultipa.ResultType_RESULT_TYPE_EDGE:
+		if d.Edges == nil {
+			return []byte("[]"), nil
+		}
+		return json.Marshal(d.Edges)
+	case ultipa.ResultType_RESULT_TYPE_PATH:
+		if d.Paths == nil {
+			return []byte("[]"), nil
+		}
+		return json.Marshal(d.Paths)
+	default:
+		if d.Attrs == nil {
+			return []byte("[]"), nil
+		}
+		return json.Marshal(d.Attrs)
+	}
+}
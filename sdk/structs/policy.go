@@ -0,0 +1,79 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Policy is a policy listed by show().policy(), policies can be nested in other policies
+type Policy struct {
+	Name               string
+	GraphPrivileges    GraphPrivileges
+	SystemPrivileges   []string
+	PropertyPrivileges *PropertyPrivileges
+	Policies           []string
+}
+
+// NewPolicy parses a row of _policy table, privileges and policies are json strings
+func NewPolicy(name string, graphPrivileges string, systemPrivileges string, propertyPrivileges string, policies string) (*Policy, error) {
+	policy := &Policy{
+		Name:            name,
+		GraphPrivileges: GraphPrivileges{},
+	}
+
+	if strings.TrimSpace(graphPrivileges) != "" {
+		err := json.Unmarshal([]byte(graphPrivileges), &policy.GraphPrivileges)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("failed to parse graph privileges of policy %s: %v", name, err))
+		}
+	}
+
+	var err error
+	policy.SystemPrivileges, err = parseStringList(systemPrivileges)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to parse system privileges of policy %s: %v", name, err))
+	}
+	policy.PropertyPrivileges, err = ParsePropertyPrivileges(propertyPrivileges)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to parse property privileges of policy %s: %v", name, err))
+	}
+	policy.Policies, err = parseStringList(policies)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to parse policies of policy %s: %v", name, err))
+	}
+	return policy, nil
+}
+
+// NewPrivileges parses the row of _privilege table, each column is a json array of privilege names
+func NewPrivileges(graphPrivileges string, systemPrivileges string, propertyPrivileges string) ([]*Privilege, error) {
+	var privileges []*Privilege
+	for _, column := range []struct {
+		level PrivilegeLevel
+		value string
+	}{
+		{PrivilegeLevelGraph, graphPrivileges},
+		{PrivilegeLevelSystem, systemPrivileges},
+		{PrivilegeLevelProperty, propertyPrivileges},
+	} {
+		names, err := parseStringList(column.value)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("failed to parse %s privileges: %v", column.level, err))
+		}
+		for _, name := range names {
+			privileges = append(privileges, &Privilege{Name: name, Level: column.level})
+		}
+	}
+	return privileges, nil
+}
+
+// PrivilegeSet returns the privileges and nested policies of the policy
+func (policy *Policy) PrivilegeSet() *PrivilegeSet {
+	return &PrivilegeSet{
+		GraphPrivileges:    policy.GraphPrivileges,
+		SystemPrivileges:   policy.SystemPrivileges,
+		PropertyPrivileges: policy.PropertyPrivileges,
+		Policies:           policy.Policies,
+	}
+}
@@ -0,0 +1,199 @@
+package structs
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+type PrivilegeLevel string
+
+const (
+	PrivilegeLevelGraph    PrivilegeLevel = "graph"
+	PrivilegeLevelSystem   PrivilegeLevel = "system"
+	PrivilegeLevelProperty PrivilegeLevel = "property"
+)
+
+// Privilege is a privilege listed by show().privilege()
+type Privilege struct {
+	Name  string
+	Level PrivilegeLevel
+}
+
+// PropertyPrivilege properties that are able to be read, written or denied, each item is [graph, schema, property], * matches all
+type PropertyPrivilege struct {
+	Read  [][]string `json:"read"`
+	Write [][]string `json:"write"`
+	Deny  [][]string `json:"deny"`
+}
+
+// PropertyPrivileges property privileges of nodes and edges
+type PropertyPrivileges struct {
+	Node PropertyPrivilege `json:"node"`
+	Edge PropertyPrivilege `json:"edge"`
+}
+
+// PrivilegeSet privileges and policies of a user or a policy, nil fields mean unknown or unchanged
+type PrivilegeSet struct {
+	GraphPrivileges    GraphPrivileges
+	SystemPrivileges   []string
+	PropertyPrivileges *PropertyPrivileges
+	Policies           []string
+}
+
+// ParsePropertyPrivileges parses {"node": {"read": [], "write": [], "deny": []}, "edge": {...}}
+func ParsePropertyPrivileges(str string) (*PropertyPrivileges, error) {
+	privileges := &PropertyPrivileges{}
+	if strings.TrimSpace(str) == "" {
+		return privileges, nil
+	}
+	err := json.Unmarshal([]byte(str), privileges)
+	if err != nil {
+		return nil, err
+	}
+	return privileges, nil
+}
+
+// IsEmpty returns true if there is nothing in the set
+func (s *PrivilegeSet) IsEmpty() bool {
+	if s == nil {
+		return true
+	}
+	for _, privileges := range s.GraphPrivileges {
+		if len(privileges) > 0 {
+			return false
+		}
+	}
+	return len(s.SystemPrivileges) == 0 && len(s.Policies) == 0 && s.PropertyPrivileges.IsEmpty()
+}
+
+// IsEmpty returns true if there is no property privilege
+func (p *PropertyPrivileges) IsEmpty() bool {
+	if p == nil {
+		return true
+	}
+	for _, privilege := range []PropertyPrivilege{p.Node, p.Edge} {
+		if len(privilege.Read) > 0 || len(privilege.Write) > 0 || len(privilege.Deny) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffPrivilegeSets returns what to grant and what to revoke to move current to desired,
+// nil fields of desired are left as they are
+func DiffPrivilegeSets(current *PrivilegeSet, desired *PrivilegeSet) (grant *PrivilegeSet, revoke *PrivilegeSet) {
+	if current == nil {
+		current = &PrivilegeSet{}
+	}
+	grant = &PrivilegeSet{}
+	revoke = &PrivilegeSet{}
+
+	if desired.GraphPrivileges != nil {
+		grant.GraphPrivileges = GraphPrivileges{}
+		revoke.GraphPrivileges = GraphPrivileges{}
+		for _, graph := range mergeStrings(current.GraphPrivileges.Graphs(), desired.GraphPrivileges.Graphs()) {
+			added, removed := diffStrings(current.GraphPrivileges[graph], desired.GraphPrivileges[graph])
+			if len(added) > 0 {
+				grant.GraphPrivileges[graph] = added
+			}
+			if len(removed) > 0 {
+				revoke.GraphPrivileges[graph] = removed
+			}
+		}
+	}
+	if desired.SystemPrivileges != nil {
+		grant.SystemPrivileges, revoke.SystemPrivileges = diffStrings(current.SystemPrivileges, desired.SystemPrivileges)
+	}
+	if desired.Policies != nil {
+		grant.Policies, revoke.Policies = diffStrings(current.Policies, desired.Policies)
+	}
+	if desired.PropertyPrivileges != nil {
+		currentProperty := current.PropertyPrivileges
+		if currentProperty == nil {
+			currentProperty = &PropertyPrivileges{}
+		}
+		grant.PropertyPrivileges = &PropertyPrivileges{}
+		revoke.PropertyPrivileges = &PropertyPrivileges{}
+		diffPropertyPrivilege(&currentProperty.Node, &desired.PropertyPrivileges.Node, &grant.PropertyPrivileges.Node, &revoke.PropertyPrivileges.Node)
+		diffPropertyPrivilege(&currentProperty.Edge, &desired.PropertyPrivileges.Edge, &grant.PropertyPrivileges.Edge, &revoke.PropertyPrivileges.Edge)
+	}
+
+	return compactPrivilegeSet(grant), compactPrivilegeSet(revoke)
+}
+
+// compactPrivilegeSet sets empty fields to nil, so they are skipped when granting or revoking
+func compactPrivilegeSet(s *PrivilegeSet) *PrivilegeSet {
+	if len(s.GraphPrivileges) == 0 {
+		s.GraphPrivileges = nil
+	}
+	if len(s.SystemPrivileges) == 0 {
+		s.SystemPrivileges = nil
+	}
+	if len(s.Policies) == 0 {
+		s.Policies = nil
+	}
+	if s.PropertyPrivileges.IsEmpty() {
+		s.PropertyPrivileges = nil
+	}
+	return s
+}
+
+func diffPropertyPrivilege(current *PropertyPrivilege, desired *PropertyPrivilege, grant *PropertyPrivilege, revoke *PropertyPrivilege) {
+	grant.Read, revoke.Read = diffPropertyItems(current.Read, desired.Read)
+	grant.Write, revoke.Write = diffPropertyItems(current.Write, desired.Write)
+	grant.Deny, revoke.Deny = diffPropertyItems(current.Deny, desired.Deny)
+}
+
+func diffPropertyItems(current [][]string, desired [][]string) (added [][]string, removed [][]string) {
+	key := func(item []string) string {
+		return strings.Join(item, "\x00")
+	}
+	currentKeys := map[string]bool{}
+	for _, item := range current {
+		currentKeys[key(item)] = true
+	}
+	desiredKeys := map[string]bool{}
+	for _, item := range desired {
+		desiredKeys[key(item)] = true
+		if !currentKeys[key(item)] {
+			added = append(added, item)
+		}
+	}
+	for _, item := range current {
+		if !desiredKeys[key(item)] {
+			removed = append(removed, item)
+		}
+	}
+	return added, removed
+}
+
+// diffStrings returns the sorted items only in desired, and the sorted items only in current
+func diffStrings(current []string, desired []string) (added []string, removed []string) {
+	currentItems := map[string]bool{}
+	for _, item := range current {
+		currentItems[item] = true
+	}
+	desiredItems := map[string]bool{}
+	for _, item := range desired {
+		desiredItems[item] = true
+	}
+	for item := range desiredItems {
+		if !currentItems[item] {
+			added = append(added, item)
+		}
+	}
+	for item := range currentItems {
+		if !desiredItems[item] {
+			removed = append(removed, item)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func mergeStrings(a []string, b []string) []string {
+	merged, _ := diffStrings(nil, append(append([]string{}, a...), b...))
+	return merged
+}
@@ -0,0 +1,9 @@
+package structs
+
+// Process is a running uql listed by top()
+type Process struct {
+	Id       string
+	Uql      string
+	Duration string // the running time reported by server
+	Status   string
+}
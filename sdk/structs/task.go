@@ -0,0 +1,165 @@
+package structs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Task is an algorithm task created by exec task, listed by show().task()
+type Task struct {
+	Id               string
+	ServerId         string
+	Algo             string
+	Status           ultipa.TASK_STATUS // from TASK_STATUS of task_info
+	StartTime        time.Time          // zero if not started
+	WritingStartTime time.Time          // zero if not writing
+	EndTime          time.Time          // zero if not finished
+	TimeCost         int                // seconds
+	EngineCost       int                // seconds
+	Info             map[string]interface{}
+	Params           map[string]interface{}
+	Result           map[string]interface{}
+	ResultFiles      []string // file names in Result, download them by DownloadFileV2
+}
+
+// NewTask parses a row of _task table, each column is a json string
+func NewTask(taskInfo string, param string, result string) (*Task, error) {
+	task := &Task{}
+	var err error
+
+	task.Info, err = parseTaskJSON(taskInfo)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to parse task_info %s: %v", taskInfo, err))
+	}
+	task.Params, err = parseTaskJSON(param)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to parse task param %s: %v", param, err))
+	}
+	task.Result, err = parseTaskJSON(result)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to parse task result %s: %v", result, err))
+	}
+
+	task.Id = valueString(task.Info["task_id"])
+	task.ServerId = valueString(task.Info["server_id"])
+	task.Algo = valueString(task.Info["algo_name"])
+	task.Status = ultipa.TASK_STATUS(valueInt(task.Info["TASK_STATUS"]))
+	task.StartTime = valueTime(task.Info["start_time"])
+	task.WritingStartTime = valueTime(task.Info["writing_start_time"])
+	task.EndTime = valueTime(task.Info["end_time"])
+	task.TimeCost = valueInt(task.Info["time_cost"])
+	task.EngineCost = valueInt(task.Info["engine_cost"])
+	task.ResultFiles = taskResultFiles(task.Result)
+
+	return task, nil
+}
+
+// IsFinished returns true if the task is done, failed or stopped
+func (task *Task) IsFinished() bool {
+	switch task.Status {
+	case ultipa.TASK_STATUS_TASK_DONE, ultipa.TASK_STATUS_TASK_FAILED, ultipa.TASK_STATUS_TASK_STOPPED:
+		return true
+	}
+	return false
+}
+
+func (task *Task) IsDone() bool {
+	return task.Status == ultipa.TASK_STATUS_TASK_DONE
+}
+
+// StatusName returns the status without the TASK_ prefix, e.g. DONE
+func (task *Task) StatusName() string {
+	return strings.TrimPrefix(task.Status.String(), "TASK_")
+}
+
+func parseTaskJSON(str string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if strings.TrimSpace(str) == "" {
+		return values, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(str)))
+	decoder.UseNumber()
+	var v interface{}
+	err := decoder.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := v.(map[string]interface{}); ok {
+		return m, nil
+	}
+	// e.g. a plain message of a failed task
+	values["value"] = v
+	return values, nil
+}
+
+func valueString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func valueInt(v interface{}) int {
+	i, _ := strconv.ParseFloat(valueString(v), 64)
+	return int(i)
+}
+
+// valueTime parses unix seconds or a time string, 0 and empty values are zero time
+func valueTime(v interface{}) time.Time {
+	str := valueString(v)
+	if str == "" || str == "0" {
+		return time.Time{}
+	}
+	if seconds, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return time.Unix(seconds, 0)
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05"} {
+		if t, err := time.ParseInLocation(layout, str, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// taskResultFiles collects the values of result keys containing "file", multiple files may be separated by ","
+func taskResultFiles(result map[string]interface{}) []string {
+	var keys []string
+	for key := range result {
+		if strings.Contains(strings.ToLower(key), "file") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var files []string
+	for _, key := range keys {
+		var names []string
+		switch v := result[key].(type) {
+		case string:
+			names = strings.Split(v, ",")
+		case []interface{}:
+			for _, name := range v {
+				names = append(names, valueString(name))
+			}
+		}
+		for _, name := range names {
+			if name = strings.TrimSpace(name); name != "" {
+				files = append(files, name)
+			}
+		}
+	}
+	return files
+}
@@ -0,0 +1,91 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GraphPrivileges privileges by graph name, e.g. {"default": ["FIND", "INSERT"]}
+type GraphPrivileges map[string][]string
+
+// User is a user listed by show().user()
+type User struct {
+	Username           string
+	Password           string    // only used by CreateUser and AlterUser, never returned by server
+	CreatedTime        time.Time // zero if unknown
+	GraphPrivileges    GraphPrivileges
+	SystemPrivileges   []string
+	PropertyPrivileges *PropertyPrivileges // nil if unknown
+	Policies           []string
+}
+
+// NewUser parses a row of _user table, privileges and policies are json strings
+func NewUser(username string, created string, graphPrivileges string, systemPrivileges string, policies string) (*User, error) {
+	user := &User{
+		Username:        username,
+		CreatedTime:     valueTime(created),
+		GraphPrivileges: GraphPrivileges{},
+	}
+
+	if strings.TrimSpace(graphPrivileges) != "" {
+		err := json.Unmarshal([]byte(graphPrivileges), &user.GraphPrivileges)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("failed to parse graph privileges of user %s: %v", username, err))
+		}
+	}
+
+	var err error
+	user.SystemPrivileges, err = parseStringList(systemPrivileges)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to parse system privileges of user %s: %v", username, err))
+	}
+	user.Policies, err = parseStringList(policies)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to parse policies of user %s: %v", username, err))
+	}
+	return user, nil
+}
+
+// PrivilegeSet returns the privileges and policies of the user
+func (user *User) PrivilegeSet() *PrivilegeSet {
+	return &PrivilegeSet{
+		GraphPrivileges:    user.GraphPrivileges,
+		SystemPrivileges:   user.SystemPrivileges,
+		PropertyPrivileges: user.PropertyPrivileges,
+		Policies:           user.Policies,
+	}
+}
+
+// Graphs returns the graph names that the user has privileges on, sorted
+func (privileges GraphPrivileges) Graphs() []string {
+	var graphs []string
+	for graph := range privileges {
+		graphs = append(graphs, graph)
+	}
+	sort.Strings(graphs)
+	return graphs
+}
+
+// parseStringList parses a json array of strings, or names separated by ","
+func parseStringList(str string) ([]string, error) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return []string{}, nil
+	}
+	if strings.HasPrefix(str, "[") {
+		list := []string{}
+		err := json.Unmarshal([]byte(str), &list)
+		return list, err
+	}
+	list := []string{}
+	for _, item := range strings.Split(str, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,56 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var serverVersionRegexp = regexp.MustCompile(`v?(\d+)\.(\d+)(?:\.(\d+))?([-+][0-9A-Za-z.+-]*)?`)
+
+// ServerVersion a semantic version of ultipa server, parsed from the version of stats()
+type ServerVersion struct {
+	Major  int
+	Minor  int
+	Patch  int
+	Suffix string // pre-release or build of the version, e.g. -beta of 4.3.0-beta
+	Raw    string
+}
+
+// ParseServerVersion finds the first major.minor[.patch] in raw, e.g. 4.3.0, v4.3.1-beta or Ultipa 4.3 build 2023
+func ParseServerVersion(raw string) (*ServerVersion, error) {
+	match := serverVersionRegexp.FindStringSubmatch(raw)
+	if match == nil {
+		return nil, errors.New(fmt.Sprintf("invalid server version: %s", raw))
+	}
+	version := &ServerVersion{Suffix: match[4], Raw: raw}
+	version.Major, _ = strconv.Atoi(match[1])
+	version.Minor, _ = strconv.Atoi(match[2])
+	if match[3] != "" {
+		version.Patch, _ = strconv.Atoi(match[3])
+	}
+	return version, nil
+}
+
+func (v *ServerVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d%s", v.Major, v.Minor, v.Patch, v.Suffix)
+}
+
+// Compare compares major, minor and patch, returns -1, 0 or 1, the suffix is ignored
+func (v *ServerVersion) Compare(other *ServerVersion) int {
+	for _, diff := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if diff < 0 {
+			return -1
+		}
+		if diff > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast returns whether v is the same as or newer than min
+func (v *ServerVersion) AtLeast(min *ServerVersion) bool {
+	return v.Compare(min) >= 0
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
@@ -51,3 +52,41 @@ func PointFromStr(pointStr string) (*Point, error) {
 func (p *Point) String() string {
 	return fmt.Sprintf(`POINT(%f %f)`, p.Latitude, p.Longitude)
 }
+
+// MarshalJSON encodes a point as {"latitude": 1.5, "longitude": 2.5}
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	}{p.Latitude, p.Longitude})
+}
+
+// UnmarshalJSON decodes a point from {"latitude": 1.5, "longitude": 2.5} or "POINT(1.5 2.5)"
+func (p *Point) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		point, err := PointFromStr(str)
+		if err != nil {
+			return err
+		}
+		*p = *point
+		return nil
+	}
+
+	var point struct {
+		Latitude  *float64 `json:"latitude"`
+		Longitude *float64 `json:"longitude"`
+	}
+	if err := json.Unmarshal(data, &point); err != nil {
+		return err
+	}
+	if point.Latitude == nil || point.Longitude == nil {
+		return errors.New(fmt.Sprintf("%s is not a valid point, latitude and longitude are required", string(data)))
+	}
+	p.Latitude = *point.Latitude
+	p.Longitude = *point.Longitude
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/ultipa/ultipa-go-sdk/sdk/api"
 	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
 	"github.com/ultipa/ultipa-go-sdk/sdk/connection"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils/logger"
 )
 
 var (
@@ -28,5 +29,12 @@ func NewUltipa(config *configuration.UltipaConfig) (*api.UltipaAPI, error) {
 		return nil, err
 	}
 
-	return api.NewUltipaAPI(pool), err
+	client := api.NewUltipaAPI(pool)
+
+	// versions are detected when connected, hosts added later are detected by Capabilities, features not supported by the server fail early
+	if err := client.DetectServerVersions(); err != nil {
+		logger.PrintWarn(err.Error())
+	}
+
+	return client, nil
 }
@@ -0,0 +1,424 @@
+// Package uqlbuilder provides a typed fluent builder for UQL statements, values and names are escaped while building.
+//
+// Usage:
+//
+//	uql, err := uqlbuilder.New().
+//		FindNodes(uqlbuilder.And(uqlbuilder.Schema("account"), uqlbuilder.Gt("age", 18))).As("n").
+//		Return("n{*}").Limit(10).
+//		Build()
+//	// find().nodes({@account && age > 18}) as n return n{*} limit 10
+package uqlbuilder
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"strconv"
+	"strings"
+)
+
+// Direction of edges, used by khop(), ab() and spread()
+type Direction string
+
+const (
+	DirectionLeft  Direction = "left"
+	DirectionRight Direction = "right"
+)
+
+type Builder struct {
+	uql string
+	err error
+	// position of the ")" that closes the last n()/e()/le()/re() of a path template, -1 if not in a template
+	templateEnd int
+	// whether the last template element is an edge, steps can only follow an edge
+	templateEdge bool
+}
+
+func New() *Builder {
+	return &Builder{templateEnd: -1}
+}
+
+// FindNodes find().nodes({filter})
+func (b *Builder) FindNodes(filter Filter) *Builder {
+	return b.statement("find().nodes", filter)
+}
+
+// FindEdges find().edges({filter})
+func (b *Builder) FindEdges(filter Filter) *Builder {
+	return b.statement("find().edges", filter)
+}
+
+// DeleteNodes delete().nodes({filter})
+func (b *Builder) DeleteNodes(filter Filter) *Builder {
+	return b.statement("delete().nodes", filter)
+}
+
+// DeleteEdges delete().edges({filter})
+func (b *Builder) DeleteEdges(filter Filter) *Builder {
+	return b.statement("delete().edges", filter)
+}
+
+// UpdateNodes update().nodes({filter}).set({values})
+func (b *Builder) UpdateNodes(filter Filter, values map[string]interface{}) *Builder {
+	return b.statement("update().nodes", filter).set(values)
+}
+
+// UpdateEdges update().edges({filter}).set({values})
+func (b *Builder) UpdateEdges(filter Filter, values map[string]interface{}) *Builder {
+	return b.statement("update().edges", filter).set(values)
+}
+
+// InsertNodes insert().into(@schema).nodes([{row1}, {row2}])
+func (b *Builder) InsertNodes(schema string, rows ...map[string]interface{}) *Builder {
+	return b.write("insert", schema, "nodes", rows)
+}
+
+// InsertEdges insert().into(@schema).edges([{row1}, {row2}]), rows should contain _from and _to
+func (b *Builder) InsertEdges(schema string, rows ...map[string]interface{}) *Builder {
+	return b.write("insert", schema, "edges", rows)
+}
+
+// UpsertNodes upsert().into(@schema).nodes([{row1}, {row2}])
+func (b *Builder) UpsertNodes(schema string, rows ...map[string]interface{}) *Builder {
+	return b.write("upsert", schema, "nodes", rows)
+}
+
+// UpsertEdges upsert().into(@schema).edges([{row1}, {row2}])
+func (b *Builder) UpsertEdges(schema string, rows ...map[string]interface{}) *Builder {
+	return b.write("upsert", schema, "edges", rows)
+}
+
+// Khop khop(), followed by Src, Depth, NodeFilter, EdgeFilter, Direction ...
+func (b *Builder) Khop() *Builder {
+	return b.start("khop()")
+}
+
+// Ab ab(), followed by Src, Dest, Depth, Shortest, NodeFilter, EdgeFilter, Direction ...
+func (b *Builder) Ab() *Builder {
+	return b.start("ab()")
+}
+
+// Spread spread(), followed by Src, Depth, NodeFilter, EdgeFilter, Direction ...
+func (b *Builder) Spread() *Builder {
+	return b.start("spread()")
+}
+
+// N a node of path template, n({filter}), starts a new template if not following an edge
+func (b *Builder) N(filter Filter) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.templateEnd >= 0 && !b.templateEdge {
+		return b.fail(errors.New("uqlbuilder: n() should follow e(), le() or re() in a path template"))
+	}
+	if b.templateEnd < 0 {
+		b.start("")
+	} else {
+		b.uql += "."
+	}
+	return b.templateElement("n", filter, false)
+}
+
+// E an edge of path template in any direction, e({filter})
+func (b *Builder) E(filter Filter) *Builder {
+	return b.templateEdgeElement("e", filter)
+}
+
+// Le a left direction edge of path template, le({filter})
+func (b *Builder) Le(filter Filter) *Builder {
+	return b.templateEdgeElement("le", filter)
+}
+
+// Re a right direction edge of path template, re({filter})
+func (b *Builder) Re(filter Filter) *Builder {
+	return b.templateEdgeElement("re", filter)
+}
+
+// Steps set steps of the last template edge, renders [min:max], or [min] when min equals max
+func (b *Builder) Steps(min int, max int) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.templateEnd < 0 || !b.templateEdge {
+		return b.fail(errors.New("uqlbuilder: steps should follow e(), le() or re() in a path template"))
+	}
+	if min < 0 || max < min {
+		return b.fail(errors.New(fmt.Sprintf("uqlbuilder: invalid steps [%d:%d]", min, max)))
+	}
+	if min == max {
+		b.uql += fmt.Sprintf("[%d]", min)
+	} else {
+		b.uql += fmt.Sprintf("[%d:%d]", min, max)
+	}
+	return b
+}
+
+// Shortest set the last template edge as shortest path with max steps, renders [*:max]
+func (b *Builder) Shortest(max int) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.templateEnd < 0 || !b.templateEdge {
+		return b.fail(errors.New("uqlbuilder: shortest steps should follow e(), le() or re() in a path template"))
+	}
+	b.uql += fmt.Sprintf("[*:%d]", max)
+	return b
+}
+
+// Alias name the last template node or edge, renders n({filter} as alias)
+func (b *Builder) Alias(alias string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.templateEnd < 0 {
+		return b.fail(errors.New("uqlbuilder: Alias should follow n(), e(), le() or re(), use As to name a statement"))
+	}
+	name, err := escapeAlias(alias)
+	if err != nil {
+		return b.fail(err)
+	}
+	insert := " as " + name
+	if b.uql[b.templateEnd-1] == '(' {
+		insert = "as " + name
+	}
+	b.uql = b.uql[:b.templateEnd] + insert + b.uql[b.templateEnd:]
+	b.templateEnd += len(insert)
+	return b
+}
+
+// Src src({filter})
+func (b *Builder) Src(filter Filter) *Builder {
+	return b.Call("src", filter)
+}
+
+// Dest dest({filter})
+func (b *Builder) Dest(filter Filter) *Builder {
+	return b.Call("dest", filter)
+}
+
+// Depth depth(depth)
+func (b *Builder) Depth(depth int) *Builder {
+	return b.Call("depth", depth)
+}
+
+// DepthRange depth(min:max)
+func (b *Builder) DepthRange(min int, max int) *Builder {
+	return b.Call("depth", utils.UqlRaw(fmt.Sprintf("%d:%d", min, max)))
+}
+
+// NodeFilter node_filter({filter})
+func (b *Builder) NodeFilter(filter Filter) *Builder {
+	return b.Call("node_filter", filter)
+}
+
+// EdgeFilter edge_filter({filter})
+func (b *Builder) EdgeFilter(filter Filter) *Builder {
+	return b.Call("edge_filter", filter)
+}
+
+// Direction direction(left|right)
+func (b *Builder) Direction(direction Direction) *Builder {
+	return b.Call("direction", utils.UqlRaw(direction))
+}
+
+// ShortestPath shortest(), used by ab()
+func (b *Builder) ShortestPath() *Builder {
+	return b.Call("shortest")
+}
+
+// Select select(property1, property2)
+func (b *Builder) Select(properties ...string) *Builder {
+	args := make([]interface{}, len(properties))
+	for i, property := range properties {
+		args[i] = utils.UqlName(property)
+	}
+	return b.Call("select", args...)
+}
+
+// Call append any chained method .name(arg1, arg2), Filter arguments are wrapped by {}, others are formatted by utils.FormatUqlValue
+func (b *Builder) Call(name string, args ...interface{}) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.uql == "" || b.templateEnd >= 0 {
+		return b.fail(errors.New(fmt.Sprintf("uqlbuilder: .%s() should follow a statement", name)))
+	}
+	var formatted []string
+	for _, arg := range args {
+		var value string
+		var err error
+		if filter, ok := arg.(Filter); ok {
+			value, err = filterToUql(filter)
+		} else {
+			value, err = utils.FormatUqlValue(arg)
+		}
+		if err != nil {
+			return b.fail(err)
+		}
+		formatted = append(formatted, value)
+	}
+	b.uql += "." + name + "(" + strings.Join(formatted, ", ") + ")"
+	return b
+}
+
+// As name the result of current statement, renders `as alias`
+func (b *Builder) As(alias string) *Builder {
+	name, err := escapeAlias(alias)
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.clause("as " + name)
+}
+
+// With with alias1, alias2
+func (b *Builder) With(aliases ...string) *Builder {
+	return b.clause("with " + strings.Join(aliases, ", "))
+}
+
+// GroupBy group by expr1, expr2
+func (b *Builder) GroupBy(exprs ...string) *Builder {
+	return b.clause("group by " + strings.Join(exprs, ", "))
+}
+
+// OrderBy order by expr1, expr2, e.g. OrderBy("n.age desc", "n._uuid")
+func (b *Builder) OrderBy(exprs ...string) *Builder {
+	return b.clause("order by " + strings.Join(exprs, ", "))
+}
+
+// Return return expr1, expr2, e.g. Return("n{*}", "count(n)")
+func (b *Builder) Return(exprs ...string) *Builder {
+	return b.clause("return " + strings.Join(exprs, ", "))
+}
+
+// Skip skip n
+func (b *Builder) Skip(n int) *Builder {
+	return b.clause("skip " + strconv.Itoa(n))
+}
+
+// Limit limit n
+func (b *Builder) Limit(n int) *Builder {
+	return b.clause("limit " + strconv.Itoa(n))
+}
+
+// Raw append a trusted uql fragment as it is, separated by a space
+func (b *Builder) Raw(fragment string) *Builder {
+	return b.clause(fragment)
+}
+
+// Build returns the uql, or the first error occurred while building
+func (b *Builder) Build() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	return b.uql, nil
+}
+
+// String returns the uql, an empty string if any error occurred while building
+func (b *Builder) String() string {
+	uql, _ := b.Build()
+	return uql
+}
+
+func (b *Builder) fail(err error) *Builder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// start a new statement
+func (b *Builder) start(statement string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.uql != "" {
+		b.uql += " "
+	}
+	b.uql += statement
+	b.templateEnd = -1
+	return b
+}
+
+func (b *Builder) statement(command string, filter Filter) *Builder {
+	expr, err := filterToUql(filter)
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.start(command + "(" + expr + ")")
+}
+
+func (b *Builder) clause(clause string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.uql == "" {
+		return b.fail(errors.New("uqlbuilder: " + clause + " should follow a statement"))
+	}
+	b.uql += " " + clause
+	b.templateEnd = -1
+	return b
+}
+
+func (b *Builder) set(values map[string]interface{}) *Builder {
+	if b.err != nil {
+		return b
+	}
+	formatted, err := utils.FormatUqlValue(values)
+	if err != nil {
+		return b.fail(err)
+	}
+	b.uql += ".set(" + formatted + ")"
+	return b
+}
+
+func (b *Builder) write(command string, schema string, dbType string, rows []map[string]interface{}) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if len(rows) == 0 {
+		return b.fail(errors.New(fmt.Sprintf("uqlbuilder: %s requires at least one row", command)))
+	}
+	name, err := utils.EscapeUqlName(schema)
+	if err != nil {
+		return b.fail(err)
+	}
+	var values string
+	if len(rows) == 1 {
+		values, err = utils.FormatUqlValue(rows[0])
+	} else {
+		values, err = utils.FormatUqlValue(rows)
+	}
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.start(fmt.Sprintf("%s().into(@%s).%s(%s)", command, name, dbType, values))
+}
+
+func (b *Builder) templateEdgeElement(name string, filter Filter) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.templateEnd < 0 || b.templateEdge {
+		return b.fail(errors.New(fmt.Sprintf("uqlbuilder: %s() should follow n() in a path template", name)))
+	}
+	b.uql += "."
+	return b.templateElement(name, filter, true)
+}
+
+func (b *Builder) templateElement(name string, filter Filter, isEdge bool) *Builder {
+	expr, err := filterToUql(filter)
+	if err != nil {
+		return b.fail(err)
+	}
+	b.uql += name + "(" + expr + ")"
+	b.templateEnd = len(b.uql) - 1
+	b.templateEdge = isEdge
+	return b
+}
+
+func escapeAlias(alias string) (string, error) {
+	if identifierMatcher.MatchString(alias) {
+		return alias, nil
+	}
+	return utils.EscapeUqlName(alias)
+}
@@ -0,0 +1,209 @@
+package uqlbuilder
+
+import (
+	"errors"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"regexp"
+	"strings"
+)
+
+// Filter is an uql filter expression, rendered inside {} of nodes(), edges(), n(), src() ...
+type Filter interface {
+	Uql() (string, error)
+}
+
+var identifierMatcher = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+type exprFilter struct {
+	expr   string
+	params map[string]interface{}
+}
+
+type compareFilter struct {
+	property string
+	operator string
+	value    interface{}
+}
+
+type logicFilter struct {
+	operator string
+	filters  []Filter
+}
+
+type notFilter struct {
+	filter Filter
+}
+
+// Expr a raw filter expression, $placeholders in expr are bound by params, check utils.BindUqlParams
+// Usage: Expr("age > $age && city == $city", map[string]interface{}{"age": 18, "city": "Beijing"})
+func Expr(expr string, params map[string]interface{}) Filter {
+	return &exprFilter{expr: expr, params: params}
+}
+
+// Schema filter by schema, renders @schema
+func Schema(name string) Filter {
+	return &exprFilter{expr: "@$schema", params: map[string]interface{}{"schema": utils.UqlName(name)}}
+}
+
+// ID filter by _id, renders _id == "id" for a single id, otherwise _id in ["id1", "id2"]
+func ID(ids ...string) Filter {
+	if len(ids) == 1 {
+		return Eq("_id", ids[0])
+	}
+	return In("_id", ids)
+}
+
+// UUID filter by _uuid, renders _uuid == 1 for a single uuid, otherwise _uuid in [1, 2]
+func UUID(uuids ...uint64) Filter {
+	if len(uuids) == 1 {
+		return Eq("_uuid", uuids[0])
+	}
+	return In("_uuid", uuids)
+}
+
+// Eq renders property == value
+func Eq(property string, value interface{}) Filter {
+	return &compareFilter{property: property, operator: "==", value: value}
+}
+
+// Ne renders property != value
+func Ne(property string, value interface{}) Filter {
+	return &compareFilter{property: property, operator: "!=", value: value}
+}
+
+// Gt renders property > value
+func Gt(property string, value interface{}) Filter {
+	return &compareFilter{property: property, operator: ">", value: value}
+}
+
+// Ge renders property >= value
+func Ge(property string, value interface{}) Filter {
+	return &compareFilter{property: property, operator: ">=", value: value}
+}
+
+// Lt renders property < value
+func Lt(property string, value interface{}) Filter {
+	return &compareFilter{property: property, operator: "<", value: value}
+}
+
+// Le renders property <= value
+func Le(property string, value interface{}) Filter {
+	return &compareFilter{property: property, operator: "<=", value: value}
+}
+
+// In renders property in [values]
+func In(property string, values interface{}) Filter {
+	return &compareFilter{property: property, operator: "in", value: values}
+}
+
+// Nin renders property nin [values]
+func Nin(property string, values interface{}) Filter {
+	return &compareFilter{property: property, operator: "nin", value: values}
+}
+
+// Contains renders property contains value, used by full-text search
+func Contains(property string, value interface{}) Filter {
+	return &compareFilter{property: property, operator: "contains", value: value}
+}
+
+// And renders (f1 && f2 && ...), nil filters are skipped
+func And(filters ...Filter) Filter {
+	return &logicFilter{operator: " && ", filters: filters}
+}
+
+// Or renders (f1 || f2 || ...), nil filters are skipped
+func Or(filters ...Filter) Filter {
+	return &logicFilter{operator: " || ", filters: filters}
+}
+
+// Not renders !(f)
+func Not(filter Filter) Filter {
+	return &notFilter{filter: filter}
+}
+
+func (f *exprFilter) Uql() (string, error) {
+	return utils.BindUqlParams(f.expr, f.params)
+}
+
+func (f *compareFilter) Uql() (string, error) {
+	property, err := escapeProperty(f.property)
+	if err != nil {
+		return "", err
+	}
+	value, err := utils.FormatUqlValue(f.value)
+	if err != nil {
+		return "", err
+	}
+	return property + " " + f.operator + " " + value, nil
+}
+
+func (f *logicFilter) Uql() (string, error) {
+	expr, count, err := f.join()
+	if err != nil || count < 2 {
+		return expr, err
+	}
+	return "(" + expr + ")", nil
+}
+
+// join renders the filters joined by operator without the surrounding (), and returns how many filters are joined
+func (f *logicFilter) join() (string, int, error) {
+	var exprs []string
+	for _, filter := range f.filters {
+		if filter == nil {
+			continue
+		}
+		expr, err := filter.Uql()
+		if err != nil {
+			return "", 0, err
+		}
+		if expr != "" {
+			exprs = append(exprs, expr)
+		}
+	}
+	return strings.Join(exprs, f.operator), len(exprs), nil
+}
+
+func (f *notFilter) Uql() (string, error) {
+	if f.filter == nil {
+		return "", errors.New("uqlbuilder: Not requires a filter")
+	}
+	expr, err := f.filter.Uql()
+	if err != nil {
+		return "", err
+	}
+	return "!(" + expr + ")", nil
+}
+
+// escapeProperty escapes each part of a dotted property, e.g. n.my name => n.`my name`
+func escapeProperty(property string) (string, error) {
+	parts := strings.Split(property, ".")
+	for i, part := range parts {
+		if identifierMatcher.MatchString(part) {
+			continue
+		}
+		escaped, err := utils.EscapeUqlName(part)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = escaped
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// filterToUql renders filter wrapped by {}, a nil filter renders as empty
+func filterToUql(filter Filter) (string, error) {
+	if filter == nil {
+		return "", nil
+	}
+	var expr string
+	var err error
+	if logic, ok := filter.(*logicFilter); ok {
+		expr, _, err = logic.join()
+	} else {
+		expr, err = filter.Uql()
+	}
+	if err != nil {
+		return "", err
+	}
+	return "{" + expr + "}", nil
+}
@@ -1,5 +1,10 @@
 package utils
 
+import (
+	"errors"
+	"fmt"
+)
+
 //LeaderNotYetElectedError leader not yet elected error for cluster
 type LeaderNotYetElectedError struct {
 	Message string
@@ -17,3 +22,117 @@ func NewLeaderNotYetElectedError(msg string) *LeaderNotYetElectedError {
 		Message: msg,
 	}
 }
+
+// SchemaNotFoundError the schema does not exist in the graph
+type SchemaNotFoundError struct {
+	Graph  string
+	DBType string // node or edge
+	Schema string
+}
+
+func (err *SchemaNotFoundError) Error() string {
+	return fmt.Sprintf("%s schema @%s is not found in graph %s", err.DBType, err.Schema, err.Graph)
+}
+
+func NewSchemaNotFoundError(graph string, dbType string, schema string) *SchemaNotFoundError {
+	return &SchemaNotFoundError{
+		Graph:  graph,
+		DBType: dbType,
+		Schema: schema,
+	}
+}
+
+// SchemaExistsError the schema exists in the graph already
+type SchemaExistsError struct {
+	Graph  string
+	DBType string // node or edge
+	Schema string
+}
+
+func (err *SchemaExistsError) Error() string {
+	return fmt.Sprintf("%s schema @%s exists in graph %s already", err.DBType, err.Schema, err.Graph)
+}
+
+func NewSchemaExistsError(graph string, dbType string, schema string) *SchemaExistsError {
+	return &SchemaExistsError{
+		Graph:  graph,
+		DBType: dbType,
+		Schema: schema,
+	}
+}
+
+// SchemaOperationError the server failed to alter, drop or create the schema
+type SchemaOperationError struct {
+	Operation string // alter, drop or copy
+	Graph     string
+	DBType    string // node or edge
+	Schema    string
+	Message   string
+}
+
+func (err *SchemaOperationError) Error() string {
+	return fmt.Sprintf("failed to %s %s schema @%s of graph %s: %s", err.Operation, err.DBType, err.Schema, err.Graph, err.Message)
+}
+
+func NewSchemaOperationError(operation string, graph string, dbType string, schema string, message string) *SchemaOperationError {
+	return &SchemaOperationError{
+		Operation: operation,
+		Graph:     graph,
+		DBType:    dbType,
+		Schema:    schema,
+		Message:   message,
+	}
+}
+
+// ErrPermissionDenied is matched by errors.Is of a PermissionDeniedError
+var ErrPermissionDenied = errors.New("permission denied")
+
+// PermissionDeniedError the user has no permission of the operation, returned by the preflight of a request
+type PermissionDeniedError struct {
+	User      string
+	Graph     string
+	Operation string
+	Uql       string // empty if the operation is not an uql
+	Message   string
+}
+
+func (err *PermissionDeniedError) Error() string {
+	msg := fmt.Sprintf("permission denied: user %s can not %s on graph %s", err.User, err.Operation, err.Graph)
+	if err.Message != "" {
+		msg += ": " + err.Message
+	}
+	return msg
+}
+
+func (err *PermissionDeniedError) Is(target error) bool {
+	return target == ErrPermissionDenied
+}
+
+func NewPermissionDeniedError(user string, graph string, operation string, uql string, message string) *PermissionDeniedError {
+	return &PermissionDeniedError{
+		User:      user,
+		Graph:     graph,
+		Operation: operation,
+		Uql:       uql,
+		Message:   message,
+	}
+}
+
+// UnsupportedFeatureError the server is older than the version required by the feature
+type UnsupportedFeatureError struct {
+	Feature         string
+	RequiredVersion string
+	ServerVersion   string
+}
+
+func (err *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("%s requires server version %s or later, but the server version is %s", err.Feature, err.RequiredVersion, err.ServerVersion)
+}
+
+func NewUnsupportedFeatureError(feature string, requiredVersion string, serverVersion string) *UnsupportedFeatureError {
+	return &UnsupportedFeatureError{
+		Feature:         feature,
+		RequiredVersion: requiredVersion,
+		ServerVersion:   serverVersion,
+	}
+}
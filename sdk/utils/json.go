@@ -0,0 +1,228 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/types"
+	"strconv"
+	"time"
+)
+
+// NormalizeJSONValue converts a value decoded by a json.Decoder with UseNumber to plain go values:
+// integer numbers to int64 (uint64 if too large), other numbers to float64, {"latitude","longitude"} objects to *types.Point.
+func NormalizeJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, element := range v {
+			list[i] = NormalizeJSONValue(element)
+		}
+		return list
+	case map[string]interface{}:
+		if point, ok := jsonObjectAsPoint(v); ok {
+			return point
+		}
+		m := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			m[key] = NormalizeJSONValue(element)
+		}
+		return m
+	default:
+		return value
+	}
+}
+
+// DecodeJSONValue decodes data to a value normalized by NormalizeJSONValue
+func DecodeJSONValue(data []byte) (interface{}, error) {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return NormalizeJSONValue(value), nil
+}
+
+// JSONValueAsInterface converts a value decoded from json to the go type that ConvertBytesToInterface returns for PropertyType t,
+// DATETIME and TIMESTAMP are RFC3339 strings, BLOB is a base64 string, POINT is an object with latitude and longitude.
+func JSONValueAsInterface(value interface{}, t ultipa.PropertyType, subTypes []ultipa.PropertyType) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch t {
+	case ultipa.PropertyType_STRING, ultipa.PropertyType_TEXT:
+		if str, ok := value.(string); ok {
+			return str, nil
+		}
+	case ultipa.PropertyType_INT32, ultipa.PropertyType_INT64, ultipa.PropertyType_UINT32, ultipa.PropertyType_UINT64,
+		ultipa.PropertyType_FLOAT, ultipa.PropertyType_DOUBLE:
+		if str, ok := jsonNumberString(value); ok {
+			return StringAsInterface(str, t, nil)
+		}
+	case ultipa.PropertyType_DATETIME:
+		switch v := value.(type) {
+		case *UltipaTime:
+			return v, nil
+		case string:
+			if parsed, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return TimeToUltipaTime(&parsed, parsed.Location()), nil
+			}
+			return NewDatetimeFromString(v)
+		}
+		if str, ok := jsonNumberString(value); ok && str == "0" {
+			return uint64(0), nil
+		}
+	case ultipa.PropertyType_TIMESTAMP:
+		switch v := value.(type) {
+		case *UltipaTime:
+			return v, nil
+		case string:
+			if parsed, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return NewTimeStamp(parsed.Unix()), nil
+			}
+			return NewTimestampFromString(v, nil)
+		}
+		if str, ok := jsonNumberString(value); ok {
+			seconds, err := strconv.ParseInt(str, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return NewTimeStamp(seconds), nil
+		}
+	case ultipa.PropertyType_BLOB:
+		switch v := value.(type) {
+		case []byte:
+			return v, nil
+		case string:
+			return base64.StdEncoding.DecodeString(v)
+		}
+	case ultipa.PropertyType_POINT:
+		switch v := value.(type) {
+		case *types.Point:
+			return v, nil
+		case types.Point:
+			return &v, nil
+		case string:
+			return types.PointFromStr(v)
+		case map[string]interface{}:
+			if point, ok := jsonObjectAsPoint(v); ok {
+				return point, nil
+			}
+		}
+	case ultipa.PropertyType_LIST:
+		list, ok := value.([]interface{})
+		if !ok {
+			break
+		}
+		if len(subTypes) == 0 {
+			return nil, errors.New("subTypes is not specified, unable to convert json list")
+		}
+		result := make([]interface{}, len(list))
+		for i, element := range list {
+			converted, err := JSONValueAsInterface(element, subTypes[0], nil)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = converted
+		}
+		return result, nil
+	case ultipa.PropertyType_NULL_, ultipa.PropertyType_UNSET:
+		return nil, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("unsupported ultipa.PropertyType [%s] for json value", t))
+	}
+
+	return nil, errors.New(fmt.Sprintf("json value %v of type %T can not be converted to %s", value, value, t))
+}
+
+// InferJSONValueType guesses the PropertyType of a value normalized by NormalizeJSONValue, used when no schema is available
+func InferJSONValueType(value interface{}) (ultipa.PropertyType, []ultipa.PropertyType) {
+	switch v := value.(type) {
+	case string:
+		return ultipa.PropertyType_STRING, nil
+	case int32:
+		return ultipa.PropertyType_INT32, nil
+	case int64, int:
+		return ultipa.PropertyType_INT64, nil
+	case uint32:
+		return ultipa.PropertyType_UINT32, nil
+	case uint64:
+		return ultipa.PropertyType_UINT64, nil
+	case float32:
+		return ultipa.PropertyType_FLOAT, nil
+	case float64:
+		return ultipa.PropertyType_DOUBLE, nil
+	case *types.Point, types.Point:
+		return ultipa.PropertyType_POINT, nil
+	case *UltipaTime, UltipaTime:
+		return ultipa.PropertyType_DATETIME, nil
+	case []byte:
+		return ultipa.PropertyType_BLOB, nil
+	case []interface{}:
+		for _, element := range v {
+			if element != nil {
+				subType, _ := InferJSONValueType(element)
+				return ultipa.PropertyType_LIST, []ultipa.PropertyType{subType}
+			}
+		}
+		return ultipa.PropertyType_LIST, []ultipa.PropertyType{ultipa.PropertyType_STRING}
+	default:
+		return ultipa.PropertyType_NULL_, nil
+	}
+}
+
+func jsonNumberString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String(), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case int32, int, uint32:
+		return fmt.Sprint(v), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+func jsonObjectAsPoint(object map[string]interface{}) (*types.Point, bool) {
+	if len(object) != 2 {
+		return nil, false
+	}
+	latitude, ok := jsonNumberString(object["latitude"])
+	if !ok {
+		return nil, false
+	}
+	longitude, ok := jsonNumberString(object["longitude"])
+	if !ok {
+		return nil, false
+	}
+	lat, err := strconv.ParseFloat(latitude, 64)
+	if err != nil {
+		return nil, false
+	}
+	lng, err := strconv.ParseFloat(longitude, 64)
+	if err != nil {
+		return nil, false
+	}
+	return types.NewPoint(lat, lng), true
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
@@ -475,6 +476,40 @@ func (u *UltipaTime) String() string {
 	return u.Time.Format("2006-01-02T15:04:05.000Z07:00")
 }
 
+// MarshalJSON encodes UltipaTime as a RFC3339 string with fraction seconds, e.g. "2000-01-02T03:04:05.123456Z"
+func (u UltipaTime) MarshalJSON() ([]byte, error) {
+	t := u.Time
+	if t == nil {
+		if u.Datetime == 0 {
+			return []byte("null"), nil
+		}
+		t = NewDateTime(u.Datetime).Time
+	}
+	return json.Marshal(t.Format(time.RFC3339Nano))
+}
+
+// UnmarshalJSON decodes UltipaTime from a RFC3339 string, or any layout supported by NewUltipaTimeFromString
+func (u *UltipaTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	t, err := time.Parse(time.RFC3339Nano, str)
+	if err != nil {
+		ultipaTime, err := NewUltipaTimeFromString(str, nil)
+		if err != nil {
+			return err
+		}
+		*u = *ultipaTime
+		return nil
+	}
+	*u = *TimeToUltipaTime(&t, t.Location())
+	return nil
+}
+
 // Get Timestamp , Second
 func (u *UltipaTime) GetTimeStamp() uint32 {
 	return uint32(u.Time.Unix())
@@ -6,15 +6,65 @@ import (
 )
 
 /**
-check if uql has update delete or insert operations
+check if uql has update delete or insert operations, check ClassifyUql for how uql is classified
 */
 
 type UqlItem struct {
-	Uql []byte
+	Uql            []byte
+	classification *UqlClassification
 }
 
+// ParseGraphCommands commands whose graph is given by .graph("name"), e.g. mount().graph("name")
+var ParseGraphCommands = map[string]struct{}{
+	"mount":    {},
+	"unmount":  {},
+	"truncate": {},
+	"compact":  {},
+}
+
+// WriteUqlCommands commands to be sent to the leader, matched by head, head() or head().second in lower case
+var WriteUqlCommands = map[string]struct{}{
+	"create": {}, "alter": {}, "drop": {}, "grant": {}, "revoke": {},
+	"lte": {}, "ufe": {}, "truncate": {}, "compact": {},
+	"insert": {}, "update": {}, "delete": {}, "upsert": {},
+	"clear": {}, "stop": {}, "pause": {}, "resume": {},
+	"top": {}, "kill": {},
+	"mount().graph": {}, "unmount().graph": {},
+}
+
+// GlobalUqlCommands commands to be sent to the global graphset, matched by head() or head().second in lower case
+var GlobalUqlCommands = map[string]struct{}{
+	"show().user":      {},
+	"get().user":       {},
+	"create().user":    {},
+	"delete().user":    {},
+	"drop().user":      {},
+	"grant().user":     {},
+	"revoke().user":    {},
+	"alter().user":     {},
+	"show().policy":    {},
+	"get().policy":     {},
+	"create().policy":  {},
+	"delete().policy":  {},
+	"drop().policy":    {},
+	"alter().policy":   {},
+	"grant().policy":   {},
+	"revoke().policy":  {},
+	"show().privilege": {},
+	"stats()":          {},
+	"show().graph":     {},
+	"get().graph":      {},
+	"create().graph":   {},
+	"alter().graph":    {},
+	"drop().graph":     {},
+	"kill()":           {},
+	"top()":            {},
+}
+
+// Deprecated: ParseGraphCommandKeys is no longer used to classify uql, use ParseGraphCommands instead
 var ParseGraphCommandKeys = `(mount|unmount|truncate)\(\s*\)\.graph\(\s*["'](?P<graph>\w+)["']\s*\)`
 
+// Deprecated: WriteUqlCommandKeys is no longer used to classify uql, use WriteUqlCommands instead
 var WriteUqlCommandKeys = []string{
 	"create", "alter", "drop", "grant", "revoke",
 	"LTE", "UFE", "truncate", "compact",
@@ -24,6 +74,7 @@ var WriteUqlCommandKeys = []string{
 	`mount\(\).graph`, `unmount\(\).graph`,
 }
 
+// Deprecated: GlobalUqlCommandKeys is no longer used to classify uql, use GlobalUqlCommands instead
 var GlobalUqlCommandKeys = []string{
 	`show\(\).user`,
 	`get\(\).user`,
@@ -50,6 +101,7 @@ var GlobalUqlCommandKeys = []string{
 	`^\s*top\(\)`,
 }
 
+// ExtraUqlCommandKeys commands to be sent to UqlEx of the control client, matched by the first command chain of uql
 var ExtraUqlCommandKeys = map[string]struct{}{
 	`top()`:       {},
 	`kill`:        {},
@@ -59,6 +111,7 @@ var ExtraUqlCommandKeys = map[string]struct{}{
 	`stats()`:      {},
 	`show().graph`: {},
 	`show().algo`:  {},
+	`show().exta`:  {},
 	//`create().policy`:  {},
 	//`drop().policy`:    {},
 	`show().policy`: {},
@@ -83,78 +136,38 @@ func NewUql(uql string) *UqlItem {
 	}
 }
 
+// Classify tokenizes the uql and classifies it, the result is cached
+func (t *UqlItem) Classify() *UqlClassification {
+	if t.classification == nil {
+		t.classification = ClassifyUql(string(t.Uql))
+	}
+	return t.classification
+}
+
 func (t *UqlItem) HasWith() bool {
-	matcher := GetUqlRegExpMatcher([]string{"with"})
-	return matcher.Match(t.Uql)
+	return t.Classify().With
 }
 
 func (t *UqlItem) HasWrite() bool {
-	matcher := GetUqlRegExpMatcher(WriteUqlCommandKeys)
-	return matcher.Match(t.Uql)
+	return t.Classify().Write
 }
 
 func (t *UqlItem) HasExecTask() bool {
-	matcher := GetUqlRegExpMatcher([]string{`exec task`})
-	return matcher.Match(t.Uql)
+	return t.Classify().ExecTask
 }
 
 //IsGlobal check the uql needs global graphset
 func (t *UqlItem) IsGlobal() bool {
-	matcher := GetUqlRegExpMatcher(GlobalUqlCommandKeys)
-	return matcher.Match(t.Uql)
+	return t.Classify().Global
 }
 
 //IsExtra check whether the uql is extra, if yes, then it should be sent to uqlEx via ControlClient
 func (t *UqlItem) IsExtra() bool {
-	matcher := regexp.MustCompile(`(?P<first>[a-z_A-Z]*)(?:\((?P<param>[^\(|^\)]*)\))?(?:[.]*(?P<second>[a-z_A-Z]*))*`)
-	result := matcher.FindStringSubmatch(strings.TrimSpace(string(t.Uql)))
-	if len(result) == 0 {
-		return false
-	}
-	if _, ok := ExtraUqlCommandKeys[result[0]]; ok {
-		return true
-	}
-
-	firstCmd := ""
-	param := ""
-	secondCmd := ""
-	firstIdx := matcher.SubexpIndex("first")
-	if firstIdx > -1 {
-		firstCmd = result[firstIdx]
-	}
-	paramIdx := matcher.SubexpIndex("param")
-	if paramIdx > -1 {
-		param = result[paramIdx]
-	}
-	secondIdx := matcher.SubexpIndex("second")
-	if secondIdx > -1 {
-		secondCmd = result[secondIdx]
-	}
-
-	if firstCmd != "" && secondCmd != "" {
-		if _, ok := ExtraUqlCommandKeys[firstCmd+"()."+secondCmd]; ok {
-			return true
-		}
-	}
-
-	//主要处理类似kill(1)命令中有参数的语句
-	_, firstCmdIsExtra := ExtraUqlCommandKeys[firstCmd]
-	if len(result) > 1 && result[0] != "" && firstCmdIsExtra && param != "" {
-		return true
-	}
-	return false
+	return t.Classify().Extra
 }
 
 //ParseGraph check whether fetch graph name from uql or not
 func (t *UqlItem) ParseGraph() (bool, string) {
-	matcher := regexp.MustCompile(ParseGraphCommandKeys)
-	result := matcher.FindSubmatch(t.Uql)
-	if result != nil {
-		idx := matcher.SubexpIndex("graph")
-		if idx > -1 {
-			return true, string(result[idx])
-		}
-		return false, ""
-	}
-	return false, ""
+	graph := t.Classify().Graph
+	return graph != "", graph
 }
@@ -0,0 +1,373 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type UqlTokenType int
+
+const (
+	UqlTokenIdent  UqlTokenType = iota // find, nodes, n, _uuid
+	UqlTokenString                     // "abc" or 'abc', Value is unescaped
+	UqlTokenName                       // `my name`, Value is the name without backquotes
+	UqlTokenNumber                     // 1, 1.5, 1e10
+	UqlTokenPunct                      // ( ) . { } == && ...
+)
+
+type UqlToken struct {
+	Type  UqlTokenType
+	Value string
+	Pos   int
+}
+
+// UqlCall is a function call of a command chain, e.g. nodes({_id == "A"}) of find().nodes({_id == "A"})
+type UqlCall struct {
+	Name string
+	Args []UqlToken
+}
+
+// UqlStatement is a command chain, e.g. find().nodes() or khop().src().depth()
+type UqlStatement struct {
+	Calls []UqlCall
+}
+
+// UqlClassification describes how an uql should be routed
+type UqlClassification struct {
+	Statements []UqlStatement
+	Write      bool   // contains insert, update, delete, create ...
+	Global     bool   // needs the global graphset, e.g. show().user, create().graph
+	Extra      bool   // should be sent to UqlEx of the control client, e.g. top(), show().task
+	ExecTask   bool   // contains exec task
+	With       bool   // contains with clause
+	Graph      string // graph targeted by mount().graph, unmount().graph, truncate().graph or compact().graph
+}
+
+var twoCharUqlPuncts = map[string]struct{}{
+	"==": {}, "!=": {}, ">=": {}, "<=": {}, "&&": {}, "||": {}, "<>": {}, "=>": {},
+}
+
+// TokenizeUql splits uql into tokens, comments (// and /* */) and white spaces are dropped.
+// The tokens before an unclosed string, name or comment are returned together with the error
+func TokenizeUql(uql string) ([]UqlToken, error) {
+	var tokens []UqlToken
+
+	for i := 0; i < len(uql); {
+		c := uql[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			i++
+
+		case c == '/' && i+1 < len(uql) && uql[i+1] == '/':
+			end := strings.IndexByte(uql[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end + 1
+
+		case c == '/' && i+1 < len(uql) && uql[i+1] == '*':
+			end := strings.Index(uql[i+2:], "*/")
+			if end < 0 {
+				return tokens, errors.New(fmt.Sprintf("uql has an unclosed comment at %d", i))
+			}
+			i += end + 4
+
+		case c == '"' || c == '\'':
+			value, end, ok := readUqlString(uql, i)
+			if !ok {
+				return tokens, errors.New(fmt.Sprintf("uql has an unclosed %c at %d", c, i))
+			}
+			tokens = append(tokens, UqlToken{Type: UqlTokenString, Value: value, Pos: i})
+			i = end
+
+		case c == '`':
+			end := strings.IndexByte(uql[i+1:], '`')
+			if end < 0 {
+				return tokens, errors.New(fmt.Sprintf("uql has an unclosed ` at %d", i))
+			}
+			tokens = append(tokens, UqlToken{Type: UqlTokenName, Value: uql[i+1 : i+1+end], Pos: i})
+			i += end + 2
+
+		case c >= '0' && c <= '9':
+			end := readUqlNumber(uql, i)
+			tokens = append(tokens, UqlToken{Type: UqlTokenNumber, Value: uql[i:end], Pos: i})
+			i = end
+
+		default:
+			r, size := utf8.DecodeRuneInString(uql[i:])
+			if r == '_' || unicode.IsLetter(r) {
+				end := i + size
+				for end < len(uql) {
+					r, size = utf8.DecodeRuneInString(uql[end:])
+					if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+						break
+					}
+					end += size
+				}
+				tokens = append(tokens, UqlToken{Type: UqlTokenIdent, Value: uql[i:end], Pos: i})
+				i = end
+				continue
+			}
+			if i+1 < len(uql) {
+				if _, ok := twoCharUqlPuncts[uql[i:i+2]]; ok {
+					tokens = append(tokens, UqlToken{Type: UqlTokenPunct, Value: uql[i : i+2], Pos: i})
+					i += 2
+					continue
+				}
+			}
+			tokens = append(tokens, UqlToken{Type: UqlTokenPunct, Value: uql[i : i+size], Pos: i})
+			i += size
+		}
+	}
+
+	return tokens, nil
+}
+
+// readUqlString reads the string literal starting at uql[start], returns the unescaped value and the index after the closing quote
+func readUqlString(uql string, start int) (string, int, bool) {
+	quote := uql[start]
+	var builder strings.Builder
+	for i := start + 1; i < len(uql); i++ {
+		c := uql[i]
+		if c == quote {
+			return builder.String(), i + 1, true
+		}
+		if c == '\\' && i+1 < len(uql) {
+			i++
+			switch uql[i] {
+			case 'n':
+				builder.WriteByte('\n')
+			case 't':
+				builder.WriteByte('\t')
+			case 'r':
+				builder.WriteByte('\r')
+			default:
+				builder.WriteByte(uql[i])
+			}
+			continue
+		}
+		builder.WriteByte(c)
+	}
+	return "", len(uql), false
+}
+
+func readUqlNumber(uql string, start int) int {
+	i := start
+	for i < len(uql) && uql[i] >= '0' && uql[i] <= '9' {
+		i++
+	}
+	if i+1 < len(uql) && uql[i] == '.' && uql[i+1] >= '0' && uql[i+1] <= '9' {
+		i++
+		for i < len(uql) && uql[i] >= '0' && uql[i] <= '9' {
+			i++
+		}
+	}
+	if i+1 < len(uql) && (uql[i] == 'e' || uql[i] == 'E') {
+		j := i + 1
+		if j < len(uql) && (uql[j] == '+' || uql[j] == '-') {
+			j++
+		}
+		if j < len(uql) && uql[j] >= '0' && uql[j] <= '9' {
+			for j < len(uql) && uql[j] >= '0' && uql[j] <= '9' {
+				j++
+			}
+			i = j
+		}
+	}
+	return i
+}
+
+// ParseUqlStatements collects the command chains of uql, a chain starts by the first token or an identifier followed by (,
+// which is outside of any (), and not after . or as, e.g. find().nodes(), n().e().n(), khop().src().depth()
+func ParseUqlStatements(tokens []UqlToken) []UqlStatement {
+	var statements []UqlStatement
+	depth := 0
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token.Type == UqlTokenPunct {
+			switch token.Value {
+			case "(":
+				depth++
+			case ")":
+				if depth > 0 {
+					depth--
+				}
+			}
+			continue
+		}
+		if token.Type != UqlTokenIdent || depth > 0 {
+			continue
+		}
+		if i > 0 && (isUqlPunct(tokens[i-1], ".") || isUqlKeyword(tokens[i-1], "as")) {
+			continue
+		}
+		if i != 0 && !(i+1 < len(tokens) && isUqlPunct(tokens[i+1], "(")) {
+			continue
+		}
+		statement, end := parseUqlStatement(tokens, i)
+		statements = append(statements, statement)
+		i = end - 1
+	}
+
+	return statements
+}
+
+// parseUqlStatement parses the chain starting at tokens[start], returns the chain and the index after it
+func parseUqlStatement(tokens []UqlToken, start int) (UqlStatement, int) {
+	statement := UqlStatement{}
+	i := start
+	for i < len(tokens) && tokens[i].Type == UqlTokenIdent {
+		call := UqlCall{Name: tokens[i].Value}
+		i++
+		if i < len(tokens) && isUqlPunct(tokens[i], "(") {
+			depth := 1
+			argStart := i + 1
+			i++
+			for i < len(tokens) && depth > 0 {
+				if isUqlPunct(tokens[i], "(") {
+					depth++
+				} else if isUqlPunct(tokens[i], ")") {
+					depth--
+				}
+				i++
+			}
+			argEnd := i
+			if depth == 0 {
+				argEnd--
+			}
+			call.Args = tokens[argStart:argEnd]
+		}
+		statement.Calls = append(statement.Calls, call)
+		if i+1 < len(tokens) && isUqlPunct(tokens[i], ".") && tokens[i+1].Type == UqlTokenIdent {
+			i++
+			continue
+		}
+		break
+	}
+	return statement, i
+}
+
+func isUqlPunct(token UqlToken, value string) bool {
+	return token.Type == UqlTokenPunct && token.Value == value
+}
+
+func isUqlKeyword(token UqlToken, keyword string) bool {
+	return token.Type == UqlTokenIdent && strings.EqualFold(token.Value, keyword)
+}
+
+// Command returns the lower-cased command of the chain, e.g. show().user() => show().user, top() => top()
+func (s *UqlStatement) Command() string {
+	if len(s.Calls) == 0 {
+		return ""
+	}
+	if len(s.Calls) == 1 {
+		return strings.ToLower(s.Calls[0].Name) + "()"
+	}
+	return strings.ToLower(s.Calls[0].Name) + "()." + strings.ToLower(s.Calls[1].Name)
+}
+
+// Head returns the lower-cased name of the first call of the chain
+func (s *UqlStatement) Head() string {
+	if len(s.Calls) == 0 {
+		return ""
+	}
+	return strings.ToLower(s.Calls[0].Name)
+}
+
+// matchUqlCommandKeys checks the chain by its head, head() and head().second
+func (s *UqlStatement) matchUqlCommandKeys(keys map[string]struct{}) bool {
+	head := s.Head()
+	if head == "" {
+		return false
+	}
+	if _, ok := keys[head]; ok {
+		return true
+	}
+	if _, ok := keys[head+"()"]; ok {
+		return true
+	}
+	if len(s.Calls) > 1 {
+		if _, ok := keys[head+"()."+strings.ToLower(s.Calls[1].Name)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassifyUql tokenizes uql and classifies it, string literals, escaped names and comments are never treated as commands
+func ClassifyUql(uql string) *UqlClassification {
+	// an unclosed string will be rejected by server, classify the tokens before it
+	tokens, _ := TokenizeUql(uql)
+	classification := &UqlClassification{
+		Statements: ParseUqlStatements(tokens),
+	}
+
+	depth := 0
+	for i, token := range tokens {
+		if isUqlPunct(token, "(") {
+			depth++
+		} else if isUqlPunct(token, ")") && depth > 0 {
+			depth--
+		}
+		if depth > 0 || token.Type != UqlTokenIdent || (i > 0 && isUqlPunct(tokens[i-1], ".")) {
+			continue
+		}
+		if isUqlKeyword(token, "exec") && i+1 < len(tokens) && isUqlKeyword(tokens[i+1], "task") {
+			classification.ExecTask = true
+		}
+		if isUqlKeyword(token, "with") && !(i+1 < len(tokens) && isUqlPunct(tokens[i+1], "(")) {
+			classification.With = true
+		}
+	}
+
+	for idx, statement := range classification.Statements {
+		if statement.matchUqlCommandKeys(WriteUqlCommands) {
+			classification.Write = true
+		}
+		if statement.matchUqlCommandKeys(GlobalUqlCommands) {
+			classification.Global = true
+		}
+		if idx == 0 && statement.matchUqlCommandKeys(ExtraUqlCommandKeys) {
+			classification.Extra = true
+		}
+		if classification.Graph == "" && len(statement.Calls) > 1 && strings.EqualFold(statement.Calls[1].Name, "graph") {
+			if _, ok := ParseGraphCommands[statement.Head()]; ok {
+				args := statement.Calls[1].Args
+				if len(args) == 1 && (args[0].Type == UqlTokenString || args[0].Type == UqlTokenName) {
+					classification.Graph = args[0].Value
+				}
+			}
+		}
+	}
+
+	return classification
+}
+
+// NormalizeUql joins the tokens of uql by single spaces, so uqls only differ in white spaces and comments are normalized to the same string.
+// uql is returned as it is if it can not be tokenized
+func NormalizeUql(uql string) string {
+	tokens, err := TokenizeUql(uql)
+	if err != nil {
+		return uql
+	}
+	var builder strings.Builder
+	for i, token := range tokens {
+		if i > 0 {
+			builder.WriteByte(' ')
+		}
+		switch token.Type {
+		case UqlTokenString:
+			builder.WriteString(QuoteUqlString(token.Value))
+		case UqlTokenName:
+			builder.WriteString("`" + token.Value + "`")
+		default:
+			builder.WriteString(token.Value)
+		}
+	}
+	return builder.String()
+}
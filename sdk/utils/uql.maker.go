@@ -49,30 +49,34 @@ const (
 	UQLCommand_lteEdge            UQLCommand = "LTE().edge_property"
 	UQLCommand_ufeNode            UQLCommand = "UFE().node_property"
 	UQLCommand_ufeEdge            UQLCommand = "UFE().edge_property"
-	UQLCommand_createIndex        UQLCommand = "createIndex"
-	UQLCommand_showIndex          UQLCommand = "showIndex"
-	UQLCommand_dropIndex          UQLCommand = "dropIndex"
-	UQLCommand_stat               UQLCommand = "stat"
+	UQLCommand_createIndex        UQLCommand = "create().index"
+	UQLCommand_createNodeIndex    UQLCommand = "create().node_index"
+	UQLCommand_createEdgeIndex    UQLCommand = "create().edge_index"
+	UQLCommand_showIndex          UQLCommand = "show().index"
+	UQLCommand_dropIndex          UQLCommand = "drop().index"
+	UQLCommand_dropNodeIndex      UQLCommand = "drop().node_index"
+	UQLCommand_dropEdgeIndex      UQLCommand = "drop().edge_index"
+	UQLCommand_stat               UQLCommand = "stats"
 	UQLCommand_algo               UQLCommand = "algo"
-	UQLCommand_listPrivilege      UQLCommand = "listPrivilege"
-	UQLCommand_grant              UQLCommand = "grant"
-	UQLCommand_revoke             UQLCommand = "revoke"
-	UQLCommand_listUser           UQLCommand = "listUser"
-	UQLCommand_getUser            UQLCommand = "getUser"
-	UQLCommand_createUser         UQLCommand = "createUser"
-	UQLCommand_updateUser         UQLCommand = "updateUser"
-	UQLCommand_deleteUser         UQLCommand = "deleteUser"
-	UQLCommand_createPolicy       UQLCommand = "createPolicy"
-	UQLCommand_updatePolicy       UQLCommand = "updatePolicy"
-	UQLCommand_deletePolicy       UQLCommand = "deletePolicy"
-	UQLCommand_listPolicy         UQLCommand = "listPolicy"
-	UQLCommand_getPolicy          UQLCommand = "getPolicy"
+	UQLCommand_listPrivilege      UQLCommand = "show().privilege"
+	UQLCommand_grant              UQLCommand = "grant().user"
+	UQLCommand_revoke             UQLCommand = "revoke().user"
+	UQLCommand_listUser           UQLCommand = "show().user"
+	UQLCommand_getUser            UQLCommand = "show().user"
+	UQLCommand_createUser         UQLCommand = "create().user"
+	UQLCommand_updateUser         UQLCommand = "alter().user"
+	UQLCommand_deleteUser         UQLCommand = "drop().user"
+	UQLCommand_createPolicy       UQLCommand = "create().policy"
+	UQLCommand_updatePolicy       UQLCommand = "alter().policy"
+	UQLCommand_deletePolicy       UQLCommand = "drop().policy"
+	UQLCommand_listPolicy         UQLCommand = "show().policy"
+	UQLCommand_getPolicy          UQLCommand = "show().policy"
 	UQLCommand_showTask           UQLCommand = "show().task"
 	UQLCommand_clearTask          UQLCommand = "clear().task"
-	UQLCommand_createGraph        UQLCommand = "createGraph"
-	UQLCommand_getGraph           UQLCommand = "getGraph"
-	UQLCommand_dropGraph          UQLCommand = "dropGraph"
-	UQLCommand_updateGraph        UQLCommand = "updateGraph"
+	UQLCommand_createGraph        UQLCommand = "create().graph"
+	UQLCommand_getGraph           UQLCommand = "show().graph"
+	UQLCommand_dropGraph          UQLCommand = "drop().graph"
+	UQLCommand_updateGraph        UQLCommand = "alter().graph"
 )
 
 func replace_doller(str string) string {
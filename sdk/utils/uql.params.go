@@ -0,0 +1,269 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ultipa/ultipa-go-sdk/sdk/types"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UqlName binds a parameter as an identifier, e.g. @$schema or n.$property,
+// the name is escaped as `name` if IsNeedToEscapeName
+type UqlName string
+
+// UqlQuotedName binds a parameter as a name in creating statements, e.g. create().node_schema($name),
+// the name is quoted by "" or escaped as `name` if IsNeedToEscapeName
+type UqlQuotedName string
+
+// UqlRaw binds a parameter as it is, without any escaping, make sure the value is trusted
+type UqlRaw string
+
+const uqlDatetimeLayout = "2006-01-02 15:04:05.000000"
+
+// BindUqlParams replaces every $placeholder in uql with the formatted value of params[placeholder].
+// Placeholders inside string literals and escaped names are ignored, check FormatUqlValue for how values are formatted.
+// Usage: BindUqlParams(`find().nodes({name == $name}) as n return n`, map[string]interface{}{"name": "Alice"})
+func BindUqlParams(uql string, params map[string]interface{}) (string, error) {
+	var builder strings.Builder
+	var quote byte
+
+	for i := 0; i < len(uql); i++ {
+		c := uql[i]
+
+		if quote != 0 {
+			builder.WriteByte(c)
+			if c == '\\' && quote != '`' && i+1 < len(uql) {
+				i++
+				builder.WriteByte(uql[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '"', '\'', '`':
+			quote = c
+			builder.WriteByte(c)
+			continue
+		case '$':
+			end := i + 1
+			for end < len(uql) && isUqlIdentByte(uql[end], end == i+1) {
+				end++
+			}
+			if end == i+1 {
+				builder.WriteByte(c)
+				continue
+			}
+			key := uql[i+1 : end]
+			value, ok := params[key]
+			if !ok {
+				return "", errors.New(fmt.Sprintf("uql param $%s is not provided", key))
+			}
+			formatted, err := FormatUqlValue(value)
+			if err != nil {
+				return "", errors.New(fmt.Sprintf("uql param $%s: %s", key, err.Error()))
+			}
+			builder.WriteString(formatted)
+			i = end - 1
+			continue
+		}
+		builder.WriteByte(c)
+	}
+
+	if quote != 0 {
+		return "", errors.New(fmt.Sprintf("uql has an unclosed %c", quote))
+	}
+
+	return builder.String(), nil
+}
+
+func isUqlIdentByte(c byte, first bool) bool {
+	if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+		return true
+	}
+	return !first && c >= '0' && c <= '9'
+}
+
+// FormatUqlValue formats value as an uql literal:
+// nil => null, string => "escaped string", numbers and bool as they are,
+// time.Time/UltipaTime => "2006-01-02 15:04:05.000000", types.Point => point({latitude: x, longitude: y}),
+// slices and arrays => [v1, v2], maps => {key: value}, UqlName/UqlQuotedName => escaped name, UqlRaw => as it is
+func FormatUqlValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case UqlRaw:
+		return string(v), nil
+	case UqlName:
+		return EscapeUqlName(string(v))
+	case UqlQuotedName:
+		if err := checkUqlName(string(v)); err != nil {
+			return "", err
+		}
+		if IsNeedToEscapeName(string(v)) {
+			return "`" + string(v) + "`", nil
+		}
+		return QuoteUqlString(string(v)), nil
+	case string:
+		return QuoteUqlString(v), nil
+	case []byte:
+		return QuoteUqlString(string(v)), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float32:
+		return formatUqlFloat(float64(v), 32)
+	case float64:
+		return formatUqlFloat(v, 64)
+	case time.Time:
+		return QuoteUqlString(v.Format(uqlDatetimeLayout)), nil
+	case *time.Time:
+		if v == nil {
+			return "null", nil
+		}
+		return QuoteUqlString(v.Format(uqlDatetimeLayout)), nil
+	case UltipaTime:
+		return formatUqlUltipaTime(&v), nil
+	case *UltipaTime:
+		if v == nil {
+			return "null", nil
+		}
+		return formatUqlUltipaTime(v), nil
+	case types.Point:
+		return formatUqlPoint(&v)
+	case *types.Point:
+		if v == nil {
+			return "null", nil
+		}
+		return formatUqlPoint(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.String:
+		return QuoteUqlString(rv.String()), nil
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return "null", nil
+		}
+		return FormatUqlValue(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return "[]", nil
+		}
+		items := make([]string, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item, err := FormatUqlValue(rv.Index(i).Interface())
+			if err != nil {
+				return "", err
+			}
+			items[i] = item
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return "", errors.New(fmt.Sprintf("unable to format map with %v keys as uql value", rv.Type().Key()))
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, key := range rv.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, key := range keys {
+			name, err := EscapeUqlName(key)
+			if err != nil {
+				return "", err
+			}
+			item, err := FormatUqlValue(rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())).Interface())
+			if err != nil {
+				return "", err
+			}
+			items[i] = name + ": " + item
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+	}
+
+	return "", errors.New(fmt.Sprintf("unable to format %T as uql value", value))
+}
+
+// QuoteUqlString quotes s as an uql string literal, escaping \ " and control characters
+func QuoteUqlString(s string) string {
+	var builder strings.Builder
+	builder.Grow(len(s) + 2)
+	builder.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			builder.WriteString(`\\`)
+		case '"':
+			builder.WriteString(`\"`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
+
+// EscapeUqlName returns name escaped as `name` if IsNeedToEscapeName, otherwise name itself
+func EscapeUqlName(name string) (string, error) {
+	if err := checkUqlName(name); err != nil {
+		return "", err
+	}
+	if IsNeedToEscapeName(name) {
+		return "`" + name + "`", nil
+	}
+	return name, nil
+}
+
+func checkUqlName(name string) error {
+	if name == "" {
+		return errors.New("uql name can not be empty")
+	}
+	if strings.Contains(name, "`") {
+		return errors.New(fmt.Sprintf("uql name can not contain character `, name = %s", name))
+	}
+	return nil
+}
+
+func formatUqlFloat(f float64, bitSize int) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", errors.New(fmt.Sprintf("unable to format %v as uql value", f))
+	}
+	return strconv.FormatFloat(f, 'f', -1, bitSize), nil
+}
+
+func formatUqlUltipaTime(t *UltipaTime) string {
+	if t.Time == nil {
+		t.Uint64ToTime(t.Datetime)
+	}
+	return QuoteUqlString(t.Time.Format(uqlDatetimeLayout))
+}
+
+func formatUqlPoint(p *types.Point) (string, error) {
+	latitude, err := formatUqlFloat(p.Latitude, 64)
+	if err != nil {
+		return "", err
+	}
+	longitude, err := formatUqlFloat(p.Longitude, 64)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("point({latitude: %s, longitude: %s})", latitude, longitude), nil
+}
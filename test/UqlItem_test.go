@@ -11,3 +11,157 @@ func TestIsGlobalUql(t *testing.T) {
 	isGlobal := uqlItem.IsGlobal()
 	t.Logf("%s is global:%v", uql, isGlobal)
 }
+
+func TestClassifyUql(t *testing.T) {
+	type expected struct {
+		write    bool
+		global   bool
+		extra    bool
+		execTask bool
+		with     bool
+		graph    string
+	}
+
+	cases := []struct {
+		uql      string
+		expected expected
+	}{
+		// reads
+		{`find().nodes() as n return n{*} limit 10`, expected{}},
+		{`find().nodes({name == "create"}) as n return n{*}`, expected{}},
+		{`find().nodes({name == 'delete().nodes()'}) as n return n{*}`, expected{}},
+		{`find().nodes({update_time > 1}) as n return n.update_time`, expected{}},
+		{`find().edges({@insert_log}) as e return e{*}`, expected{}},
+		{"find().nodes({@`update`}) as n return n{*}", expected{}},
+		{"find().nodes({`drop` == 1}) as n return n.`drop`", expected{}},
+		{`find().nodes() as update return update`, expected{}},
+		{`find().nodes({name == "a\"); delete().nodes()"}) as n return n`, expected{}},
+		{"// delete().nodes()\nfind().nodes() as n return n", expected{}},
+		{"/* create().graph(\"g\") */ find().nodes() as n return n", expected{}},
+		{`n({_id == "C001"}).e().n({@card} as neighbors) as p return p{*}`, expected{}},
+		{`khop().src({_id == "A"}).depth(2).limit(10) as k return k`, expected{}},
+		{`ab().src({_id == "A"}).dest({_id == "B"}).depth(3) as p return p`, expected{}},
+		{`show().schema()`, expected{}},
+		{`show().node_property({@customer})`, expected{}},
+		{`show().edge_schema(@amz).limit(100)`, expected{}},
+		{`algo(degree).params({})`, expected{}},
+		{`find().nodes() as n with n find().nodes({_id == n._id}) as m return m`, expected{with: true}},
+		{`find().nodes({with == 1}) as n return n`, expected{}},
+
+		// writes
+		{`insert().into(@user).nodes({name: "find"})`, expected{write: true}},
+		{`upsert().into(@user).nodes({_id: "U1"})`, expected{write: true}},
+		{`update().nodes({_id == "U1"}).set({name: "show().user"})`, expected{write: true}},
+		{`delete().edges({@follow})`, expected{write: true}},
+		{`DELETE().nodes({_id == "A"})`, expected{write: true}},
+		{`create().node_schema("account")`, expected{write: true}},
+		{`alter().node_property(@account.name).set({name: "n"})`, expected{write: true}},
+		{`drop().edge_schema(@follow)`, expected{write: true}},
+		{`LTE().node_property(@account.age)`, expected{write: true}},
+		{`UFE().node_property(@account.age)`, expected{write: true}},
+		{`compact().graph("g1")`, expected{write: true, graph: "g1"}},
+		{`clear().task(1)`, expected{write: true}},
+		{`stop().task(1)`, expected{write: true}},
+		{`pause().task(1)`, expected{write: true}},
+		{`resume().task(1)`, expected{write: true}},
+		{`n({_id == "C001"}).e().n({@card} as neighbors)
+    find().nodes({_id == "C002"}) as C002
+    with neighbors, C002
+    update().nodes({_id == neighbors._id && balance > C002.balance}).set({level: level + 1})`, expected{write: true, with: true}},
+
+		// graph targeted
+		{`mount( ).graph("abcde")`, expected{write: true, graph: "abcde"}},
+		{`mount().graph('abcde')`, expected{write: true, graph: "abcde"}},
+		{`unmount().graph("abcde")`, expected{write: true, graph: "abcde"}},
+		{`truncate().graph("abcde").nodes(@user)`, expected{write: true, graph: "abcde"}},
+		{"truncate().graph(`my graph`)", expected{write: true, graph: "my graph"}},
+		{`truncate().graph(abcde)`, expected{write: true}},
+
+		// globals
+		{`show().user()`, expected{global: true, extra: true}},
+		{`show().user("root")`, expected{global: true, extra: true}},
+		{`create().user("u", "p")`, expected{write: true, global: true}},
+		{`alter().user("u").set({password: "create"})`, expected{write: true, global: true}},
+		{`drop().user("u")`, expected{write: true, global: true}},
+		{`grant().user("u").params({graph_privileges: {}})`, expected{write: true, global: true}},
+		{`revoke().user("u").params({})`, expected{write: true, global: true}},
+		{`show().policy()`, expected{global: true, extra: true}},
+		{`create().policy("p")`, expected{write: true, global: true}},
+		{`drop().policy("p")`, expected{write: true, global: true}},
+		{`show().privilege()`, expected{global: true, extra: true}},
+		{`show().graph()`, expected{global: true, extra: true}},
+		{`show().graph("g1")`, expected{global: true, extra: true}},
+		{`create().graph("g1", "desc")`, expected{write: true, global: true}},
+		{`alter().graph("g1").set({name: "g2"})`, expected{write: true, global: true}},
+		{`drop().graph("g1")`, expected{write: true, global: true}},
+		{`stats()`, expected{global: true, extra: true}},
+		{`  top()`, expected{write: true, global: true, extra: true}},
+		{`kill("*")`, expected{write: true, global: true, extra: true}},
+		{`find().nodes({name == "show().user()"}) as n return n`, expected{}},
+
+		// extras
+		{`show().task()`, expected{extra: true}},
+		{`show().algo()`, expected{extra: true}},
+		{`show().self()`, expected{extra: true}},
+		{`show().index()`, expected{extra: true}},
+		{`find().nodes() as n return n show().task()`, expected{}},
+
+		// exec task
+		{`exec task algo(degree).params({})`, expected{execTask: true}},
+		{`EXEC  TASK algo(louvain).params({})`, expected{execTask: true}},
+		{`algo(degree).params({name: "exec task"})`, expected{}},
+	}
+
+	for _, c := range cases {
+		actual := utils.NewUql(c.uql).Classify()
+		graph := actual.Graph
+		if actual.Write != c.expected.write || actual.Global != c.expected.global || actual.Extra != c.expected.extra ||
+			actual.ExecTask != c.expected.execTask || actual.With != c.expected.with || graph != c.expected.graph {
+			t.Errorf("%s\nexpected: %+v\nactual:   write:%t global:%t extra:%t execTask:%t with:%t graph:%s",
+				c.uql, c.expected, actual.Write, actual.Global, actual.Extra, actual.ExecTask, actual.With, graph)
+		}
+	}
+}
+
+func TestTokenizeUql(t *testing.T) {
+	tokens, err := utils.TokenizeUql("find().nodes({name == \"a\\\"b\" && `my age` >= 1.5e3}) // comment\n/* block */ as n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []utils.UqlToken{
+		{Type: utils.UqlTokenIdent, Value: "find"},
+		{Type: utils.UqlTokenPunct, Value: "("},
+		{Type: utils.UqlTokenPunct, Value: ")"},
+		{Type: utils.UqlTokenPunct, Value: "."},
+		{Type: utils.UqlTokenIdent, Value: "nodes"},
+		{Type: utils.UqlTokenPunct, Value: "("},
+		{Type: utils.UqlTokenPunct, Value: "{"},
+		{Type: utils.UqlTokenIdent, Value: "name"},
+		{Type: utils.UqlTokenPunct, Value: "=="},
+		{Type: utils.UqlTokenString, Value: `a"b`},
+		{Type: utils.UqlTokenPunct, Value: "&&"},
+		{Type: utils.UqlTokenName, Value: "my age"},
+		{Type: utils.UqlTokenPunct, Value: ">="},
+		{Type: utils.UqlTokenNumber, Value: "1.5e3"},
+		{Type: utils.UqlTokenPunct, Value: "}"},
+		{Type: utils.UqlTokenPunct, Value: ")"},
+		{Type: utils.UqlTokenIdent, Value: "as"},
+		{Type: utils.UqlTokenIdent, Value: "n"},
+	}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("expected %d tokens, got %d: %+v", len(expected), len(tokens), tokens)
+	}
+	for i, token := range tokens {
+		if token.Type != expected[i].Type || token.Value != expected[i].Value {
+			t.Errorf("token[%d] expected %+v, got %+v", i, expected[i], token)
+		}
+	}
+
+	for _, uql := range []string{`find().nodes({name == "abc})`, "find().nodes({`abc})", `find() /* comment`} {
+		if _, err := utils.TokenizeUql(uql); err == nil {
+			t.Errorf("%s: expected error, got nil", uql)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"strings"
+	"testing"
+)
+
+func TestBuildAlgoUql(t *testing.T) {
+	params := map[string]interface{}{"ids": []int{1, 2}, "order": "desc", "limit": 10}
+	cases := []struct {
+		opts     *api.AlgoOptions
+		expected string
+	}{
+		{nil, `algo(degree).params({ids: [1, 2], limit: 10, order: "desc"}).stream() as results return results`},
+		{&api.AlgoOptions{Mode: api.AlgoModeStats}, `algo(degree).params({ids: [1, 2], limit: 10, order: "desc"}).stats() as stats return stats`},
+		{&api.AlgoOptions{Mode: api.AlgoModeStream, Alias: "degrees"}, `algo(degree).params({ids: [1, 2], limit: 10, order: "desc"}).stream() as degrees return degrees`},
+		{&api.AlgoOptions{Mode: api.AlgoModeWriteProperty, Property: "degree"}, `algo(degree).params({ids: [1, 2], limit: 10, order: "desc"}).write({db: {property: "degree"}})`},
+		{&api.AlgoOptions{Mode: api.AlgoModeWriteFile, FileName: "degree.csv"}, `algo(degree).params({ids: [1, 2], limit: 10, order: "desc"}).write({file: {filename: "degree.csv"}})`},
+	}
+	for _, c := range cases {
+		uql, err := api.BuildAlgoUql("degree", params, c.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uql != c.expected {
+			t.Errorf("expected %s, got %s", c.expected, uql)
+		}
+	}
+
+	for _, opts := range []*api.AlgoOptions{{Mode: api.AlgoModeWriteProperty}, {Mode: api.AlgoModeWriteFile}, {Mode: api.AlgoMode(9)}} {
+		if _, err := api.BuildAlgoUql("degree", nil, opts); err == nil {
+			t.Errorf("expected mode %d without target to fail", opts.Mode)
+		}
+	}
+}
+
+func TestValidateAlgoParams(t *testing.T) {
+	degree, err := structs.NewAlgo("degree", `{"name":"degree","parameters":{"ids":"uuids","order":"asc or desc","limit":"count"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	algos := []*structs.Algo{degree}
+
+	algo, err := api.ValidateAlgoParams(algos, "degree", map[string]interface{}{"order": "desc"})
+	if err != nil || algo != degree {
+		t.Errorf("expected valid params, got %v", err)
+	}
+	_, err = api.ValidateAlgoParams(algos, "degree", map[string]interface{}{"order": "desc", "direction": "in"})
+	if err == nil || !strings.Contains(err.Error(), "[direction]") || !strings.Contains(err.Error(), "[ids, limit, order]") {
+		t.Errorf("expected unknown param direction, got %v", err)
+	}
+	if _, err = api.ValidateAlgoParams(algos, "louvain", nil); err == nil {
+		t.Error("expected algo not installed")
+	}
+}
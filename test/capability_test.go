@@ -0,0 +1,141 @@
+package test
+
+import (
+	"errors"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"testing"
+	"time"
+)
+
+// fakeStatsServer replies version to stats()
+type fakeStatsServer struct {
+	fakeControlsServer
+	t       *testing.T
+	version string
+}
+
+func (s *fakeStatsServer) UqlEx(in *ultipa.UqlRequest, stream ultipa.UltipaControls_UqlExServer) error {
+	table := &ultipa.Table{TableName: http.RESP_STATISTIC_KEY}
+	table.Headers = append(table.Headers, &ultipa.Header{PropertyName: "version", PropertyType: ultipa.PropertyType_STRING})
+	table.TableRows = append(table.TableRows, &ultipa.TableRow{Values: [][]byte{mustBytes(s.t, s.version)}})
+	return stream.Send(&ultipa.UqlReply{
+		Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS},
+		Alias:  []*ultipa.ResultAlias{{Alias: http.RESP_STATISTIC_KEY, ResultType: ultipa.ResultType_RESULT_TYPE_TABLE}},
+		Tables: []*ultipa.Table{table},
+	})
+}
+
+// fakeHungStatsServer never replies stats() until the request is cancelled
+type fakeHungStatsServer struct {
+	fakeControlsServer
+}
+
+func (s *fakeHungStatsServer) UqlEx(in *ultipa.UqlRequest, stream ultipa.UltipaControls_UqlExServer) error {
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+func TestParseServerVersion(t *testing.T) {
+	cases := map[string]string{
+		"4.3.0":                  "4.3.0",
+		"v4.2.1-beta":            "4.2.1-beta",
+		"Ultipa 4.3 build 20230": "4.3.0",
+	}
+	for raw, expect := range cases {
+		version, err := structs.ParseServerVersion(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version.String() != expect {
+			t.Errorf("ParseServerVersion(%s) = %s, expected %s", raw, version, expect)
+		}
+	}
+	if _, err := structs.ParseServerVersion("unknown"); err == nil {
+		t.Error("expected an error of invalid version")
+	}
+
+	old, _ := structs.ParseServerVersion("4.2.9")
+	min, _ := structs.ParseServerVersion("4.3.0")
+	if old.AtLeast(min) || !min.AtLeast(old) || min.Compare(min) != 0 {
+		t.Error("unexpected comparison of versions")
+	}
+
+	if !api.NewCapabilities(nil).Supports(api.FeatureUploader) {
+		t.Error("expected features of an unknown version to be supported")
+	}
+}
+
+func TestServerCapabilities(t *testing.T) {
+	client := newFakeServerClient(t, &fakeStatsServer{t: t, version: "4.2.1"})
+
+	version := client.ServerVersion(client.Config.Hosts[0])
+	if version == nil || version.String() != "4.2.1" {
+		t.Fatalf("unexpected server version %v", version)
+	}
+	raw, err := client.GetServerVersion()
+	if err != nil || raw != "4.2.1" {
+		t.Errorf("unexpected raw version %s %v", raw, err)
+	}
+
+	capabilities := client.Capabilities()
+	if !capabilities.Supports(api.FeatureExta) || capabilities.Supports(api.FeatureUserSetting) {
+		t.Errorf("unexpected capabilities %v", capabilities.Features)
+	}
+
+	err = client.SetUserSetting("", "default_graph", "amz", nil)
+	var unsupported *utils.UnsupportedFeatureError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected an unsupported feature error, got %v", err)
+	}
+	if err.Error() != "user setting requires server version 4.3.0 or later, but the server version is 4.2.1" {
+		t.Errorf("unexpected message %s", err.Error())
+	}
+
+	// property privileges are checked before sending the uql
+	_, err = client.CreatePolicy(&structs.Policy{
+		Name:               "operator",
+		PropertyPrivileges: &structs.PropertyPrivileges{},
+	}, nil)
+	if !errors.As(err, &unsupported) || unsupported.Feature != string(api.FeaturePropertyPrivileges) {
+		t.Errorf("expected property privileges to be unsupported, got %v", err)
+	}
+}
+
+func TestCapabilitiesDetectNewHosts(t *testing.T) {
+	fake := &fakeStatsServer{t: t, version: "4.3.0"}
+	client := newFakeServerClient(t, fake)
+	if client.Capabilities().Version.String() != "4.3.0" {
+		t.Fatalf("unexpected capabilities %v", client.Capabilities().Version)
+	}
+
+	// a host added after the client is created, e.g. by RefreshClusterInfo, is detected by Capabilities
+	fake.version = "4.2.1"
+	client.Pool.Connections["127.0.0.2:60061"] = client.Pool.Connections[client.Config.Hosts[0]]
+	if version := client.Capabilities().Version; version == nil || version.String() != "4.2.1" {
+		t.Fatalf("expected the new host to be detected, got %v", version)
+	}
+	if client.ServerVersion(client.Config.Hosts[0]).String() != "4.3.0" {
+		t.Error("expected the detected host to be kept")
+	}
+}
+
+func TestDetectServerVersionTimeout(t *testing.T) {
+	timeout := api.DefaultServerVersionTimeout
+	api.DefaultServerVersionTimeout = 100 * time.Millisecond
+	defer func() {
+		api.DefaultServerVersionTimeout = timeout
+	}()
+
+	start := time.Now()
+	client := newFakeServerClient(t, &fakeHungStatsServer{})
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("expected a hung host to time out in detecting its version, took %v", time.Since(start))
+	}
+	if client.ServerVersion(client.Config.Hosts[0]) != nil || !client.Capabilities().Supports(api.FeatureUploader) {
+		t.Error("expected the version of a hung host to be unknown")
+	}
+}
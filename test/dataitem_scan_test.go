@@ -0,0 +1,142 @@
+package test
+
+import (
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/types"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+type scanLocation struct {
+	Lat float64 `ultipa:"latitude"`
+	Lng float64 `ultipa:"longitude"`
+}
+
+type scanBase struct {
+	UUID uint64 `ultipa:"_uuid"`
+}
+
+type scanPerson struct {
+	scanBase
+	ID       string       `ultipa:"_id"`
+	Name     string       `ultipa:"name"`
+	Age      int          `ultipa:"age"`
+	Score    *float64     `ultipa:"score"`
+	Birthday time.Time    `ultipa:"birthday"`
+	Location scanLocation `ultipa:"location"`
+	Tags     []string     `ultipa:"tags"`
+	Ignored  string       `ultipa:"-"`
+}
+
+func mustSafeBytes(t *testing.T, value interface{}, pt ultipa.PropertyType, subTypes ...ultipa.PropertyType) []byte {
+	bs, err := utils.ConvertInterfaceToBytesSafe(value, pt, subTypes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
+
+func scanNodesItem(t *testing.T, ageType ultipa.PropertyType, age interface{}) *http.DataItem {
+	birthday := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &http.DataItem{
+		Alias: "n",
+		Type:  ultipa.ResultType_RESULT_TYPE_NODE,
+		Data: &ultipa.NodeAlias{Alias: "n", NodeTable: &ultipa.EntityTable{
+			Schemas: []*ultipa.Schema{{SchemaName: "person", Properties: []*ultipa.Property{
+				{PropertyName: "name", PropertyType: ultipa.PropertyType_STRING},
+				{PropertyName: "age", PropertyType: ageType},
+				{PropertyName: "score", PropertyType: ultipa.PropertyType_DOUBLE},
+				{PropertyName: "birthday", PropertyType: ultipa.PropertyType_DATETIME},
+				{PropertyName: "location", PropertyType: ultipa.PropertyType_POINT},
+				{PropertyName: "tags", PropertyType: ultipa.PropertyType_LIST, SubTypes: []ultipa.PropertyType{ultipa.PropertyType_STRING}},
+			}}},
+			EntityRows: []*ultipa.EntityRow{{
+				Id:         "P1",
+				Uuid:       7,
+				SchemaName: "person",
+				Values: [][]byte{
+					mustSafeBytes(t, "Alice", ultipa.PropertyType_STRING),
+					mustSafeBytes(t, age, ageType),
+					mustSafeBytes(t, 9.5, ultipa.PropertyType_DOUBLE),
+					mustSafeBytes(t, utils.TimeToUint64(birthday), ultipa.PropertyType_DATETIME),
+					mustSafeBytes(t, types.NewPoint(1.5, 2.5), ultipa.PropertyType_POINT),
+					mustSafeBytes(t, []string{"a", "b"}, ultipa.PropertyType_LIST, ultipa.PropertyType_STRING),
+				},
+			}},
+		}},
+	}
+}
+
+func TestScanNodes(t *testing.T) {
+	var persons []*scanPerson
+	if err := scanNodesItem(t, ultipa.PropertyType_INT32, int32(30)).ScanNodes(&persons); err != nil {
+		t.Fatal(err)
+	}
+	if len(persons) != 1 {
+		t.Fatalf("expected 1 person, got %d", len(persons))
+	}
+	p := persons[0]
+	if p.ID != "P1" || p.UUID != 7 || p.Name != "Alice" || p.Age != 30 || p.Score == nil || *p.Score != 9.5 {
+		t.Errorf("unexpected person %+v", p)
+	}
+	if !p.Birthday.Equal(time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("unexpected birthday %v", p.Birthday)
+	}
+	if p.Location.Lat != 1.5 || p.Location.Lng != 2.5 {
+		t.Errorf("unexpected location %+v", p.Location)
+	}
+	if len(p.Tags) != 2 || p.Tags[0] != "a" || p.Tags[1] != "b" {
+		t.Errorf("unexpected tags %v", p.Tags)
+	}
+}
+
+func TestScanNodesError(t *testing.T) {
+	var persons []scanPerson
+	err := scanNodesItem(t, ultipa.PropertyType_STRING, "thirty").ScanNodes(&persons)
+	if err == nil || !strings.Contains(err.Error(), "age") || !strings.Contains(err.Error(), "scanPerson.Age") {
+		t.Errorf("expected an error naming the age field, got %v", err)
+	}
+
+	err = scanNodesItem(t, ultipa.PropertyType_INT64, int64(1)<<40).ScanNodes(&[]struct {
+		Age int32 `ultipa:"age"`
+	}{})
+	if err == nil || !strings.Contains(err.Error(), "overflows") {
+		t.Errorf("expected an overflow error, got %v", err)
+	}
+
+	if err = scanNodesItem(t, ultipa.PropertyType_INT32, int32(1)).ScanNodes(persons); err == nil {
+		t.Error("expected an error when dest is not a pointer")
+	}
+}
+
+func TestScanTable(t *testing.T) {
+	item := &http.DataItem{
+		Alias: "t",
+		Type:  ultipa.ResultType_RESULT_TYPE_TABLE,
+		Data: &ultipa.Table{
+			TableName: "t",
+			Headers: []*ultipa.Header{
+				{PropertyName: "city", PropertyType: ultipa.PropertyType_STRING},
+				{PropertyName: "count", PropertyType: ultipa.PropertyType_UINT64},
+			},
+			TableRows: []*ultipa.TableRow{
+				{Values: [][]byte{mustSafeBytes(t, "Beijing", ultipa.PropertyType_STRING), mustSafeBytes(t, uint64(3), ultipa.PropertyType_UINT64)}},
+				{Values: [][]byte{mustSafeBytes(t, "Shanghai", ultipa.PropertyType_STRING), mustSafeBytes(t, uint64(5), ultipa.PropertyType_UINT64)}},
+			},
+		},
+	}
+
+	var rows []struct {
+		City  string
+		Count float64
+	}
+	if err := item.ScanTable(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].City != "Beijing" || rows[1].Count != 5 {
+		t.Errorf("unexpected rows %+v", rows)
+	}
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"errors"
 	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
 	"reflect"
 	"testing"
@@ -14,3 +15,23 @@ func TestErrorType(t *testing.T) {
 		t.Log("ok")
 	}
 }
+
+func TestSchemaErrorType(t *testing.T) {
+	var err error = utils.NewSchemaNotFoundError("amz", "node", "account")
+	var notFound *utils.SchemaNotFoundError
+	if !errors.As(err, &notFound) || notFound.Schema != "account" {
+		t.Error("not instance of utils.SchemaNotFoundError")
+	}
+	if err.Error() != "node schema @account is not found in graph amz" {
+		t.Errorf("unexpected message %s", err.Error())
+	}
+
+	err = utils.NewSchemaOperationError("drop", "amz", "edge", "transfer", "permission denied")
+	var operationErr *utils.SchemaOperationError
+	if !errors.As(err, &operationErr) || operationErr.Operation != "drop" {
+		t.Error("not instance of utils.SchemaOperationError")
+	}
+	if err.Error() != "failed to drop edge schema @transfer of graph amz: permission denied" {
+		t.Errorf("unexpected message %s", err.Error())
+	}
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"strings"
+	"testing"
+)
+
+func explainTestPlan(t *testing.T, childrenNums ...uint32) *http.ExplainPlan {
+	plan := &ultipa.ExplainPlan{}
+	for i, num := range childrenNums {
+		plan.PlanNodes = append(plan.PlanNodes, &ultipa.PlanNode{
+			Alias:       string(rune('a' + i)),
+			ChildrenNum: num,
+			Uql:         `find().nodes({name == "x"})`,
+			Infos:       "cost<1>",
+		})
+	}
+	explainPlan, err := http.ParseExplainPlan(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return explainPlan
+}
+
+func TestExplainTree(t *testing.T) {
+	// a -> (b -> (c, d), e)
+	roots, err := explainTestPlan(t, 2, 2, 0, 0, 0).Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 1 || len(roots[0].Children) != 2 {
+		t.Fatalf("expected 1 root with 2 children, got %v", roots)
+	}
+	var visited []string
+	roots[0].Walk(func(node *structs.ExplainNode, depth int) error {
+		visited = append(visited, strings.Repeat("-", depth)+node.Alias)
+		return nil
+	})
+	if strings.Join(visited, ",") != "a,-b,--c,--d,-e" {
+		t.Errorf("unexpected pre-order %v", visited)
+	}
+
+	if _, err := explainTestPlan(t, 2, 0).Tree(); err == nil {
+		t.Error("expected an error for a truncated plan")
+	}
+}
+
+func TestExplainExport(t *testing.T) {
+	plan := explainTestPlan(t, 1, 0)
+
+	dot, err := plan.ToDOT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"digraph explain {", `n0 [label="a\nfind().nodes({name == \"x\"})\ncost<1>"];`, "n0 -> n1;"} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("expected %s in dot:\n%s", expected, dot)
+		}
+	}
+
+	mermaid, err := plan.ToMermaid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"flowchart TD", `n1["b<br/>find().nodes({name == #quot;x#quot;})<br/>cost#lt;1#gt;"]`, "n0 --> n1"} {
+		if !strings.Contains(mermaid, expected) {
+			t.Errorf("expected %s in mermaid:\n%s", expected, mermaid)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+// fakeExtaServer an in-process server of the exta rpcs, the install of failVersion fails
+type fakeExtaServer struct {
+	fakeControlsServer
+	t           *testing.T
+	lock        sync.Mutex
+	failVersion string
+	installed   map[string]string // exta name to version
+	calls       []string
+}
+
+func (s *fakeExtaServer) InstallExta(stream ultipa.UltipaControls_InstallExtaServer) error {
+	var yml strings.Builder
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(req.FileName, ".yml") {
+			yml.Write(req.Chunk)
+		}
+	}
+	info, err := api.ParsePackageInfo([]byte(yml.String()))
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.calls = append(s.calls, "install "+info.Version)
+	if info.Version == s.failVersion {
+		return stream.SendAndClose(&ultipa.InstallExtaReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_FAILED, Msg: "broken so"}})
+	}
+	s.installed[info.Name] = info.Version
+	return stream.SendAndClose(&ultipa.InstallExtaReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}})
+}
+
+func (s *fakeExtaServer) UninstallExta(ctx context.Context, in *ultipa.UninstallExtaRequest) (*ultipa.UninstallExtaReply, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.calls = append(s.calls, "uninstall "+in.ExtaName)
+	delete(s.installed, in.ExtaName)
+	return &ultipa.UninstallExtaReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}}, nil
+}
+
+func (s *fakeExtaServer) UqlEx(in *ultipa.UqlRequest, stream ultipa.UltipaControls_UqlExServer) error {
+	table := &ultipa.Table{TableName: http.RESP_EXTAS_KEY}
+	for _, header := range []string{"name", "version", "author", "detail"} {
+		table.Headers = append(table.Headers, &ultipa.Header{PropertyName: header, PropertyType: ultipa.PropertyType_STRING})
+	}
+	s.lock.Lock()
+	for name, version := range s.installed {
+		table.TableRows = append(table.TableRows, &ultipa.TableRow{Values: [][]byte{
+			mustBytes(s.t, name), mustBytes(s.t, version), mustBytes(s.t, "ultipa"), mustBytes(s.t, "k-hop query"),
+		}})
+	}
+	s.lock.Unlock()
+	return stream.Send(&ultipa.UqlReply{
+		Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS},
+		Alias:  []*ultipa.ResultAlias{{Alias: http.RESP_EXTAS_KEY, ResultType: ultipa.ResultType_RESULT_TYPE_TABLE}},
+		Tables: []*ultipa.Table{table},
+	})
+}
+
+func TestUpgradeExta(t *testing.T) {
+	fake := &fakeExtaServer{t: t, installed: map[string]string{"khop": "1.0"}}
+	client := newFakeServerClient(t, fake)
+
+	extas, err := client.ShowExta(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extas) != 1 || extas[0].Name != "khop" || extas[0].Version != "1.0" || extas[0].Author != "ultipa" {
+		t.Fatalf("unexpected extas %+v", extas)
+	}
+
+	fsys := fstest.MapFS{
+		"v1/libexta_khop.so": {Data: []byte("v1")},
+		"v1/khop.yml":        {Data: []byte("base:\n  name: khop\n  version: \"1.0\"\n")},
+		"v2/libexta_khop.so": {Data: []byte("v2")},
+		"v2/khop.yml":        {Data: []byte("base:\n  name: khop\n  version: \"2.0\"\n")},
+		"v3/libexta_khop.so": {Data: []byte("v3")},
+		"v3/khop.yml":        {Data: []byte("base:\n  name: khop\n  version: \"3.0\"\n")},
+	}
+	v1 := &api.PackageFiles{FS: fsys, SoFile: "v1/libexta_khop.so", YmlFile: "v1/khop.yml"}
+	v2 := &api.PackageFiles{FS: fsys, SoFile: "v2/libexta_khop.so", YmlFile: "v2/khop.yml"}
+	v3 := &api.PackageFiles{FS: fsys, SoFile: "v3/libexta_khop.so", YmlFile: "v3/khop.yml"}
+
+	// the previous package must match the installed version
+	if _, err = client.UpgradeExta(context.Background(), v3, v2, nil); err == nil || len(fake.calls) != 0 {
+		t.Errorf("expected the mismatched previous package to be rejected, got %v", err)
+	}
+
+	// the failed install is rolled back to v1
+	fake.failVersion = "2.0"
+	result, err := client.UpgradeExta(context.Background(), v2, v1, nil)
+	if err == nil || !result.RolledBack || result.Installed.Version != "1.0" {
+		t.Errorf("expected to roll back, got %+v %v", result, err)
+	}
+	if fake.installed["khop"] != "1.0" {
+		t.Errorf("unexpected installed version %s", fake.installed["khop"])
+	}
+
+	result, err = client.UpgradeExta(context.Background(), v3, v1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RolledBack || result.Previous.Version != "1.0" || result.Installed.Version != "3.0" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	expected := "uninstall khop,install 2.0,install 1.0,uninstall khop,install 3.0"
+	if strings.Join(fake.calls, ",") != expected {
+		t.Errorf("unexpected calls %v", fake.calls)
+	}
+}
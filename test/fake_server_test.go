@@ -0,0 +1,100 @@
+package test
+
+import (
+	"context"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"google.golang.org/grpc"
+	"net"
+	"sync"
+	"testing"
+)
+
+// fakeControlsServer answers the rpcs of connecting in non-raft mode, embed it to fake other rpcs of UltipaControls
+type fakeControlsServer struct {
+	ultipa.UnimplementedUltipaControlsServer
+}
+
+func (s *fakeControlsServer) SayHello(ctx context.Context, in *ultipa.HelloUltipaRequest) (*ultipa.HelloUltipaReply, error) {
+	return &ultipa.HelloUltipaReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}}, nil
+}
+
+func (s *fakeControlsServer) GetLeader(ctx context.Context, in *ultipa.GetLeaderRequest) (*ultipa.GetLeaderReply, error) {
+	return &ultipa.GetLeaderReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_NOT_RAFT_MODE}}, nil
+}
+
+// fakeUqlServer records the uql and graph of each UqlEx request, and replies success with no result
+type fakeUqlServer struct {
+	fakeControlsServer
+	lock   sync.Mutex
+	uqls   []string
+	graphs []string
+}
+
+func (s *fakeUqlServer) UqlEx(in *ultipa.UqlRequest, stream ultipa.UltipaControls_UqlExServer) error {
+	return s.reply(in, stream)
+}
+
+func (s *fakeUqlServer) rpcsServer() ultipa.UltipaRpcsServer {
+	return &fakeUqlRpcsServer{server: s}
+}
+
+func (s *fakeUqlServer) reply(in *ultipa.UqlRequest, stream interface{ Send(*ultipa.UqlReply) error }) error {
+	s.lock.Lock()
+	s.uqls = append(s.uqls, in.Uql)
+	s.graphs = append(s.graphs, in.GraphName)
+	s.lock.Unlock()
+	return stream.Send(&ultipa.UqlReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}})
+}
+
+// fakeUqlRpcsServer answers the Uql rpc of UltipaRpcs by its fakeUqlServer
+type fakeUqlRpcsServer struct {
+	ultipa.UnimplementedUltipaRpcsServer
+	server *fakeUqlServer
+}
+
+func (s *fakeUqlRpcsServer) Uql(in *ultipa.UqlRequest, stream ultipa.UltipaRpcs_UqlServer) error {
+	return s.server.reply(in, stream)
+}
+
+func (s *fakeUqlServer) lastUql() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.uqls) == 0 {
+		return ""
+	}
+	return s.uqls[len(s.uqls)-1]
+}
+
+// newFakeServerClient serves server in process, and returns a client connected to it,
+// the UltipaRpcs service is served as well if server has rpcsServer()
+func newFakeServerClient(t *testing.T, server ultipa.UltipaControlsServer) *api.UltipaAPI {
+	return newFakeServerClientWithConfig(t, server, &configuration.UltipaConfig{
+		Username: "root",
+		Password: "root",
+	})
+}
+
+// newFakeServerClientWithConfig is newFakeServerClient, the client is created by config whose Hosts is set to the fake server
+func newFakeServerClientWithConfig(t *testing.T, server ultipa.UltipaControlsServer, config *configuration.UltipaConfig) *api.UltipaAPI {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("unable to listen: ", err)
+	}
+	grpcServer := grpc.NewServer()
+	ultipa.RegisterUltipaControlsServer(grpcServer, server)
+	if rpcs, ok := server.(interface{ rpcsServer() ultipa.UltipaRpcsServer }); ok {
+		ultipa.RegisterUltipaRpcsServer(grpcServer, rpcs.rpcsServer())
+	}
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	config.Hosts = []string{listener.Addr().String()}
+	client, err := sdk.NewUltipa(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"testing"
+	"time"
+)
+
+func TestBuildTruncateGraphUql(t *testing.T) {
+	for _, c := range []struct {
+		target   *api.TruncateTarget
+		expected string
+	}{
+		{nil, `truncate().graph("my graph")`},
+		{&api.TruncateTarget{DBType: ultipa.DBType_DBNODE}, `truncate().graph("my graph").nodes(*)`},
+		{&api.TruncateTarget{DBType: ultipa.DBType_DBEDGE, Schema: "transfer"}, `truncate().graph("my graph").edges(@transfer)`},
+		{&api.TruncateTarget{DBType: ultipa.DBType_DBNODE, Schema: "my account"}, "truncate().graph(\"my graph\").nodes(@`my account`)"},
+	} {
+		uql, err := api.BuildTruncateGraphUql("my graph", c.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uql != c.expected {
+			t.Errorf("expected %s, got %s", c.expected, uql)
+		}
+		if ok, graph := utils.NewUql(uql).ParseGraph(); !ok || graph != "my graph" {
+			t.Errorf("expected graph of %s to be parsed, got %s", uql, graph)
+		}
+	}
+
+	if ok, graph := utils.NewUql(`compact().graph("amz")`).ParseGraph(); !ok || graph != "amz" {
+		t.Errorf("expected graph of compact to be parsed, got %s", graph)
+	}
+}
+
+func TestGraphAdminInvalidatesCache(t *testing.T) {
+	client := newFakeServerClient(t, &fakeUqlServer{})
+	client.QueryCache = api.NewUqlCache(10, time.Minute)
+
+	uql := "find().nodes() as n return n"
+	resp := &http.UQLResponse{Status: &http.Status{Code: 0}}
+	for name, run := range map[string]func() (*api.GraphAdminResult, error){
+		"truncate": func() (*api.GraphAdminResult, error) { return client.TruncateGraph("other", nil, nil) },
+		"compact":  func() (*api.GraphAdminResult, error) { return client.CompactGraph("other", nil) },
+		"mount":    func() (*api.GraphAdminResult, error) { return client.MountGraph("other", nil) },
+		"unmount":  func() (*api.GraphAdminResult, error) { return client.UnmountGraph("other", nil) },
+	} {
+		client.QueryCache.Set("other", uql, resp)
+		result, err := run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Graph != "other" {
+			t.Errorf("unexpected graph %s of %s", result.Graph, name)
+		}
+		if _, ok := client.QueryCache.Get("other", uql); ok {
+			t.Errorf("expected %s to invalidate the cache of graph other", name)
+		}
+	}
+}
@@ -8,9 +8,16 @@
 package test
 
 import (
+	"context"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
 	"github.com/ultipa/ultipa-go-sdk/utils"
 	"log"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestListIndex(t *testing.T) {
@@ -72,3 +79,130 @@ func TestListEdgeFullText(t *testing.T) {
 	}
 	log.Printf(utils.JSONString(indexes))
 }
+
+func TestFindIndex(t *testing.T) {
+	indexes := []*http.ResponseIndex{
+		nil,
+		{Type: ultipa.DBType_DBNODE, Indexes: []*structs.Index{
+			{Name: "name", Properties: "name", Schema: "account", Status: "DONE"},
+			{Name: "age", Properties: "age", Schema: "account", Status: "creating 40%"},
+		}},
+		{Type: ultipa.DBType_DBEDGE, Indexes: []*structs.Index{
+			{Name: "amount", Properties: "amount", Schema: "transfer", Status: "failed"},
+		}},
+	}
+
+	if index := api.FindIndex(indexes, ultipa.DBType_DBNODE, "name"); index == nil || !index.IsDone() || index.IsFailed() {
+		t.Errorf("expected index name to be done, got %+v", index)
+	}
+	if index := api.FindIndex(indexes, ultipa.DBType_DBNODE, "account.age"); index == nil || index.IsDone() || index.IsFailed() {
+		t.Errorf("expected index account.age to be building, got %+v", index)
+	}
+	if index := api.FindIndex(indexes, ultipa.DBType_DBEDGE, "amount"); index == nil || !index.IsFailed() {
+		t.Errorf("expected index amount to be failed, got %+v", index)
+	}
+	if index := api.FindIndex(indexes, ultipa.DBType_DBNODE, "missing"); index != nil {
+		t.Errorf("expected no index, got %+v", index)
+	}
+	if index := api.FindIndex(indexes, ultipa.DBType_DBEDGE, "name"); index != nil {
+		t.Errorf("expected no edge index name, got %+v", index)
+	}
+}
+
+// fakeIndexServer shows no index for the first hidden show().index(), then the index of statuses in order,
+// show().index() is blocked until the request is cancelled if block is set
+type fakeIndexServer struct {
+	fakeControlsServer
+	hidden   int
+	statuses []string
+	block    bool
+
+	lock  sync.Mutex
+	shows int
+}
+
+func (s *fakeIndexServer) UqlEx(in *ultipa.UqlRequest, stream ultipa.UltipaControls_UqlExServer) error {
+	return s.reply(in, stream)
+}
+
+func (s *fakeIndexServer) rpcsServer() ultipa.UltipaRpcsServer {
+	return &fakeIndexRpcsServer{server: s}
+}
+
+func (s *fakeIndexServer) reply(in *ultipa.UqlRequest, stream interface {
+	Send(*ultipa.UqlReply) error
+	Context() context.Context
+}) error {
+	if in.Uql != "show().index()" {
+		return stream.Send(&ultipa.UqlReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}})
+	}
+	if s.block {
+		<-stream.Context().Done()
+		return stream.Context().Err()
+	}
+	s.lock.Lock()
+	shows := s.shows
+	s.shows++
+	s.lock.Unlock()
+
+	table := &ultipa.Table{TableName: http.RESP_NODE_INDEX_KEY}
+	for _, name := range []string{"name", "properties", "schema", "status"} {
+		table.Headers = append(table.Headers, &ultipa.Header{PropertyName: name, PropertyType: ultipa.PropertyType_STRING})
+	}
+	if shows >= s.hidden {
+		status := s.statuses[len(s.statuses)-1]
+		if shows-s.hidden < len(s.statuses) {
+			status = s.statuses[shows-s.hidden]
+		}
+		table.TableRows = append(table.TableRows, &ultipa.TableRow{Values: [][]byte{
+			[]byte("account_name"), []byte("name"), []byte("account"), []byte(status),
+		}})
+	}
+	return stream.Send(&ultipa.UqlReply{
+		Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS},
+		Alias:  []*ultipa.ResultAlias{{Alias: http.RESP_NODE_INDEX_KEY, ResultType: ultipa.ResultType_RESULT_TYPE_TABLE}},
+		Tables: []*ultipa.Table{table},
+	})
+}
+
+type fakeIndexRpcsServer struct {
+	ultipa.UnimplementedUltipaRpcsServer
+	server *fakeIndexServer
+}
+
+func (s *fakeIndexRpcsServer) Uql(in *ultipa.UqlRequest, stream ultipa.UltipaRpcs_UqlServer) error {
+	return s.server.reply(in, stream)
+}
+
+func TestWaitIndexReady(t *testing.T) {
+	interval, timeout := api.DefaultIndexPollInterval, api.DefaultIndexNotFoundTimeout
+	api.DefaultIndexPollInterval, api.DefaultIndexNotFoundTimeout = 10*time.Millisecond, 200*time.Millisecond
+	defer func() {
+		api.DefaultIndexPollInterval, api.DefaultIndexNotFoundTimeout = interval, timeout
+	}()
+	ctx := context.Background()
+
+	// the index is not shown right after it is created
+	client := newFakeServerClient(t, &fakeIndexServer{hidden: 3, statuses: []string{"creating 50%", "DONE"}})
+	index, err := client.WaitIndexReady(ctx, ultipa.DBType_DBNODE, "account_name", nil)
+	if err != nil || !index.IsDone() {
+		t.Fatalf("expected the index to be done, got %v %v", index, err)
+	}
+
+	client = newFakeServerClient(t, &fakeIndexServer{hidden: 1000})
+	if _, err = client.WaitIndexReady(ctx, ultipa.DBType_DBNODE, "account_name", nil); err == nil {
+		t.Error("expected an error of index not found")
+	}
+
+	// a poll in progress is cancelled with ctx
+	client = newFakeServerClient(t, &fakeIndexServer{block: true})
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err = client.WaitIndexReady(timeoutCtx, ultipa.DBType_DBNODE, "account_name", nil); err == nil {
+		t.Error("expected an error of cancelled ctx")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("expected the poll to be cancelled with ctx, took %v", time.Since(start))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/ultipa/ultipa-go-sdk/sdk/api"
 	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
 	"log"
+	"os"
 	"strings"
 	"testing"
 )
@@ -17,12 +18,14 @@ var username string
 var password string
 var graph string
 
+// TestMain connects the server of .env, without .env only the tests of in-process fake servers can run, e.g. go test -run TestUploadGraphFiles
 func TestMain(m *testing.M) {
 	var err error
 	env, err = godotenv.Read(".env")
 
 	if err != nil {
-		log.Fatalln(err)
+		log.Println("no server to connect, only tests of fake servers can run:", err)
+		os.Exit(m.Run())
 	}
 
 	hosts = strings.Split(env["hosts"], ",")
@@ -0,0 +1,190 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeInstallServer an in-process server of the InstallAlgo rpc, show().algo() replies the algos installed
+type fakeInstallServer struct {
+	fakeControlsServer
+	t         *testing.T
+	lock      sync.Mutex
+	installed map[string]string // algo name to version
+	files     map[string][]byte
+	md5s      map[string]string
+}
+
+func (s *fakeInstallServer) InstallAlgo(stream ultipa.UltipaControls_InstallAlgoServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		s.lock.Lock()
+		s.files[req.FileName] = append(s.files[req.FileName], req.Chunk...)
+		s.md5s[req.FileName] = req.Md5
+		s.lock.Unlock()
+	}
+	return stream.SendAndClose(&ultipa.InstallAlgoReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}})
+}
+
+func (s *fakeInstallServer) UqlEx(in *ultipa.UqlRequest, stream ultipa.UltipaControls_UqlExServer) error {
+	table := &ultipa.Table{TableName: http.RESP_ALGOS_KEY}
+	for _, header := range []string{"name", "param"} {
+		table.Headers = append(table.Headers, &ultipa.Header{PropertyName: header, PropertyType: ultipa.PropertyType_STRING})
+	}
+	s.lock.Lock()
+	for name, version := range s.installed {
+		table.TableRows = append(table.TableRows, &ultipa.TableRow{Values: [][]byte{
+			mustBytes(s.t, name),
+			mustBytes(s.t, `{"name":"`+name+`","version":"`+version+`","parameters":{}}`),
+		}})
+	}
+	s.lock.Unlock()
+	return stream.Send(&ultipa.UqlReply{
+		Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS},
+		Alias:  []*ultipa.ResultAlias{{Alias: http.RESP_ALGOS_KEY, ResultType: ultipa.ResultType_RESULT_TYPE_TABLE}},
+		Tables: []*ultipa.Table{table},
+	})
+}
+
+func TestParsePackageInfo(t *testing.T) {
+	yml, err := os.ReadFile("./test_algo_lib/lpa.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := api.ParsePackageInfo(yml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "LPA" || info.Category != "community" {
+		t.Errorf("unexpected package info %+v", info)
+	}
+	if err = api.CheckPackageFiles("libplugin_lpa.so", info); err != nil {
+		t.Error(err)
+	}
+	if err = api.CheckPackageFiles("libplugin_louvain.so", info); err == nil {
+		t.Error("expected an error of mismatched package name")
+	}
+
+	info.Version = "1.0.1"
+	if err = api.CheckPackageFiles("libplugin_lpa-1.0.2.so", info); err == nil {
+		t.Error("expected an error of mismatched version")
+	}
+
+	for _, invalid := range []string{"name: lpa", "base:\n  category: community", "base:\n  name: lpa\nparam_form: [a]", "base: ["} {
+		if _, err = api.ParsePackageInfo([]byte(invalid)); err == nil {
+			t.Errorf("expected an error of yml %q", invalid)
+		}
+	}
+
+	cases := []struct {
+		a, b   string
+		expect int
+	}{{"1.10.0", "1.9", 1}, {"1.0", "1.0.0", 0}, {"v2.1", "2.2", -1}}
+	for _, c := range cases {
+		if got := api.CompareVersion(c.a, c.b); got != c.expect {
+			t.Errorf("CompareVersion(%s, %s) = %d, expected %d", c.a, c.b, got, c.expect)
+		}
+	}
+}
+
+func TestInstallAlgoDir(t *testing.T) {
+	fake := &fakeInstallServer{
+		t:         t,
+		installed: map[string]string{"lpa": "1.0.0", "louvain": "1.0.0"},
+		files:     map[string][]byte{},
+		md5s:      map[string]string{},
+	}
+	client := newFakeServerClient(t, fake)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"lpa.yml":              "base:\n  name: lpa\n  version: 1.0.0\n",
+		"libplugin_lpa.so":     "lpa",
+		"louvain.yml":          "base:\n  name: louvain\n  version: 1.1.0\n",
+		"libplugin_louvain.so": strings.Repeat("louvain", 10),
+		"degree.yml":           "base:\n  name_en: degree\n  version: 2.0\n",
+		"libplugin_degree.so":  "degree",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var progresses []api.InstallProgress
+	result, err := client.InstallAlgoDir(context.Background(), dir, &api.InstallOptions{
+		ChunkSize: 16,
+		OnProgress: func(progress *api.InstallProgress) {
+			progresses = append(progresses, *progress)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Installed) != 1 || result.Installed[0].Name != "degree" {
+		t.Errorf("unexpected installed %+v", result.Installed)
+	}
+	if len(result.Upgraded) != 1 || result.Upgraded[0].Name != "louvain" {
+		t.Errorf("unexpected upgraded %+v", result.Upgraded)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Name != "lpa" {
+		t.Errorf("unexpected skipped %+v", result.Skipped)
+	}
+
+	var sent []string
+	for name, content := range fake.files {
+		sent = append(sent, name)
+		if string(content) != files[name] {
+			t.Errorf("unexpected content of %s: %s", name, content)
+		}
+		if len(fake.md5s[name]) != 32 {
+			t.Errorf("unexpected md5 of %s: %s", name, fake.md5s[name])
+		}
+	}
+	sort.Strings(sent)
+	if strings.Join(sent, ",") != "degree.yml,libplugin_degree.so,libplugin_louvain.so,louvain.yml" {
+		t.Errorf("unexpected files sent %v", sent)
+	}
+
+	last := progresses[len(progresses)-1]
+	if last.Package != "louvain" || last.File != "louvain.yml" || last.TotalSent != last.TotalSize {
+		t.Errorf("unexpected last progress %+v", last)
+	}
+
+	// the mismatched package is rejected before uploading
+	fake.files = map[string][]byte{}
+	_, err = client.InstallAlgoFromReader(context.Background(), "libplugin_lpa.so", bytes.NewReader([]byte("lpa")),
+		"louvain.yml", strings.NewReader(files["louvain.yml"]), nil)
+	if err == nil || len(fake.files) != 0 {
+		t.Errorf("expected the mismatched package to be rejected, got %v", err)
+	}
+
+	// the legacy path based InstallAlgo uploads the files as they are
+	soPath := filepath.Join(dir, "custom_algo.so")
+	if err = os.WriteFile(soPath, []byte("custom"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reply, err := client.InstallAlgo(soPath, filepath.Join(dir, "louvain.yml"), nil)
+	if err != nil || reply.Status.ErrorCode != ultipa.ErrorCode_SUCCESS {
+		t.Fatalf("expected the legacy install to succeed, got %v %v", reply, err)
+	}
+	if string(fake.files["custom_algo.so"]) != "custom" || string(fake.files["louvain.yml"]) != files["louvain.yml"] {
+		t.Errorf("unexpected files sent by InstallAlgo %v", fake.files)
+	}
+}
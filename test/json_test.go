@@ -0,0 +1,152 @@
+package test
+
+import (
+	"encoding/json"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/types"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"google.golang.org/protobuf/proto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func jsonTestResponse(t *testing.T) *http.UQLResponse {
+	nodes := scanNodesItem(t, ultipa.PropertyType_INT32, int32(30)).Data.(*ultipa.NodeAlias)
+
+	var listValues [][]byte
+	for _, tags := range [][]string{{"a", "b"}, {}} {
+		listData := &ultipa.AttrListData{Type: ultipa.ResultType_RESULT_TYPE_ATTR}
+		for _, tag := range tags {
+			listData.Attrs = append(listData.Attrs, &ultipa.Attr{ValueType: ultipa.PropertyType_STRING, Values: [][]byte{mustBytes(t, tag)}})
+		}
+		bs, err := proto.Marshal(listData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		listValues = append(listValues, bs)
+	}
+
+	return &http.UQLResponse{
+		Status:    &http.Status{Code: ultipa.ErrorCode_SUCCESS},
+		Statistic: &http.Statistic{TotalCost: 3, EngineCost: 1},
+		AliasList: []string{"n", "t", "a", "l"},
+		Reply: &ultipa.UqlReply{
+			Alias: []*ultipa.ResultAlias{
+				{Alias: "n", ResultType: ultipa.ResultType_RESULT_TYPE_NODE},
+				{Alias: "t", ResultType: ultipa.ResultType_RESULT_TYPE_TABLE},
+				{Alias: "a", ResultType: ultipa.ResultType_RESULT_TYPE_ATTR},
+				{Alias: "l", ResultType: ultipa.ResultType_RESULT_TYPE_ATTR},
+			},
+			Nodes: []*ultipa.NodeAlias{nodes},
+			Tables: []*ultipa.Table{{
+				TableName: "t",
+				Headers:   []*ultipa.Header{{PropertyName: "count", PropertyType: ultipa.PropertyType_UINT64}},
+				TableRows: []*ultipa.TableRow{{Values: [][]byte{mustSafeBytes(t, uint64(5), ultipa.PropertyType_UINT64)}}},
+			}},
+			Attrs: []*ultipa.AttrAlias{
+				{Alias: "a", Attr: &ultipa.Attr{ValueType: ultipa.PropertyType_TIMESTAMP, Values: [][]byte{
+					mustSafeBytes(t, utils.NewTimeStamp(1600000000), ultipa.PropertyType_TIMESTAMP),
+					mustSafeBytes(t, nil, ultipa.PropertyType_TIMESTAMP),
+				}}},
+				{Alias: "l", Attr: &ultipa.Attr{ValueType: ultipa.PropertyType_LIST, Values: listValues}},
+			},
+		},
+	}
+}
+
+func TestUQLResponseJSON(t *testing.T) {
+	data, err := json.Marshal(jsonTestResponse(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`"status":{"code":"SUCCESS","message":""}`,
+		`"aliases":["n","t","a","l"]`,
+		`"id":"P1","uuid":"7","schema":"person"`,
+		`"birthday":"2000-01-02T03:04:05Z"`,
+		`"location":{"latitude":1.5,"longitude":2.5}`,
+		`"type":"string[]"`,
+		`"rows":[[5]]`,
+		`"values":[["a","b"],[]]`,
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("expected %s in %s", expected, data)
+		}
+	}
+
+	var resp http.UQLResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsSuccess() || resp.Statistic.TotalCost != 3 {
+		t.Errorf("unexpected status %+v or statistic %+v", resp.Status, resp.Statistic)
+	}
+
+	nodes, schemas, err := resp.Alias("n").AsNodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || schemas["person"] == nil || nodes[0].UUID != 7 {
+		t.Fatalf("unexpected nodes %v", nodes)
+	}
+	if age, ok := nodes[0].Get("age").(int32); !ok || age != 30 {
+		t.Errorf("expected int32 age, got %T %v", nodes[0].Get("age"), nodes[0].Get("age"))
+	}
+	birthday, ok := nodes[0].Get("birthday").(*utils.UltipaTime)
+	if !ok || !birthday.Time.Equal(time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("unexpected birthday %v", nodes[0].Get("birthday"))
+	}
+	if point, ok := nodes[0].Get("location").(*types.Point); !ok || point.Longitude != 2.5 {
+		t.Errorf("unexpected location %v", nodes[0].Get("location"))
+	}
+
+	table, err := resp.Alias("t").AsTable()
+	if err != nil || (*table.Rows[0])[0] != uint64(5) {
+		t.Errorf("unexpected table %v, err: %v", table, err)
+	}
+
+	attr, err := resp.Alias("a").AsAttr()
+	if err != nil || len(attr.Rows) != 2 || attr.Rows[1] != nil {
+		t.Fatalf("unexpected attr %v, err: %v", attr, err)
+	}
+	if ts, ok := attr.Rows[0].(*utils.UltipaTime); !ok || ts.Time.Unix() != 1600000000 {
+		t.Errorf("unexpected timestamp %v", attr.Rows[0])
+	}
+
+	again, err := json.Marshal(&resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("expected the same json after a round trip\n%s\n%s", data, again)
+	}
+}
+
+func TestPathJSON(t *testing.T) {
+	data := `{"nodes":[{"id":"A","uuid":"1","schema":"city","values":{"pop":8}},{"id":"B","uuid":2,"schema":"x","values":{"v":1.5}}],
+		"edges":[{"uuid":"3","from":"A","to":"B","from_uuid":"1","to_uuid":"2","schema":"road","values":{}}],
+		"node_schemas":[{"name":"city","type":"node","properties":[{"name":"pop","type":"uint32"}]}],
+		"edge_schemas":[]}`
+
+	var path structs.Path
+	if err := json.Unmarshal([]byte(data), &path); err != nil {
+		t.Fatal(err)
+	}
+	if len(path.Nodes) != 2 || path.Edges[0].ToUUID != 2 || path.NodeSchemas["city"].DBType != ultipa.DBType_DBNODE {
+		t.Fatalf("unexpected path %+v", path)
+	}
+	if pop, ok := path.Nodes[0].Get("pop").(uint32); !ok || pop != 8 {
+		t.Errorf("expected uint32 pop typed by schema, got %T", path.Nodes[0].Get("pop"))
+	}
+	if v, ok := path.Nodes[1].Get("v").(float64); !ok || v != 1.5 {
+		t.Errorf("expected float64 without schema, got %T", path.Nodes[1].Get("v"))
+	}
+
+	var node structs.Node
+	if err := json.Unmarshal([]byte(`{"id":"A","uuid":"x"}`), &node); err == nil {
+		t.Error("expected an invalid uuid to fail")
+	}
+}
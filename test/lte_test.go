@@ -0,0 +1,77 @@
+package test
+
+import (
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"testing"
+)
+
+func TestAlgoEngineProperties(t *testing.T) {
+	properties := api.AlgoEngineProperties(map[string]interface{}{
+		"edge_schema_property": "@transfer.amount",
+		"node_schema_property": []interface{}{"age", "@`my account`.level", "@*.age"},
+		"limit":                10,
+	})
+	var names []string
+	for _, property := range properties {
+		names = append(names, property.String())
+	}
+	expected := []string{"edge @transfer.amount", "node @*.age", "node @my account.level"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, names)
+		}
+	}
+
+	schemas := []*structs.Schema{
+		{Name: "account", DBType: ultipa.DBType_DBNODE, Properties: []*structs.Property{{Name: "age"}, {Name: "level", Lte: true}}},
+		{Name: "my account", DBType: ultipa.DBType_DBNODE, Properties: []*structs.Property{{Name: "age", Lte: true}, {Name: "level"}}},
+		{Name: "transfer", DBType: ultipa.DBType_DBEDGE, Properties: []*structs.Property{{Name: "amount"}}},
+	}
+	toLoad, err := api.FindPropertiesToLoad(schemas, properties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names = nil
+	for _, property := range toLoad {
+		names = append(names, property.String())
+	}
+	expected = []string{"edge @transfer.amount", "node @account.age", "node @my account.level"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, names)
+		}
+	}
+
+	if _, err = api.FindPropertiesToLoad(schemas, api.AlgoEngineProperties(map[string]interface{}{"edge_property": "weight"})); err == nil {
+		t.Error("expected missing property to fail")
+	}
+}
+
+func TestEnginePropertyMatchesTaskParams(t *testing.T) {
+	property := &api.EngineProperty{DBType: ultipa.DBType_DBNODE, Schema: "account", Property: "age"}
+	cases := []struct {
+		params  map[string]interface{}
+		matched bool
+	}{
+		{map[string]interface{}{"property": "@account.age"}, true},
+		{map[string]interface{}{"schema": "account", "property": "age"}, true},
+		{map[string]interface{}{"schema": "`account`", "property": "`age`"}, true},
+		{map[string]interface{}{"property": "@account.level"}, false},
+		{map[string]interface{}{"schema": "card", "property": "age"}, false},
+		{map[string]interface{}{}, false},
+		{nil, false},
+	}
+	for i, c := range cases {
+		if matched := property.MatchesTaskParams(c.params); matched != c.matched {
+			t.Errorf("case %d: expected %v to be matched = %t", i, c.params, c.matched)
+		}
+	}
+}
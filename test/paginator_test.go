@@ -0,0 +1,78 @@
+package test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/uqlbuilder"
+	"testing"
+)
+
+func TestPaginator(t *testing.T) {
+	client := &api.UltipaAPI{Config: &configuration.UltipaConfig{CurrentGraph: "g1"}}
+
+	nodes := client.NewNodePaginator("account", uqlbuilder.Gt("age", 18), 100, nil)
+	uql, err := nodes.Uql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `find().nodes({@account && age > 18}) as nodes order by nodes._uuid limit 100 return nodes{*}`; uql != expected {
+		t.Errorf("expected %s, got %s", expected, uql)
+	}
+
+	edges := client.NewEdgePaginator("", nil, 0, nil)
+	uql, err = edges.Uql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `find().edges() as edges order by edges._uuid limit 1000 return edges{*}`; uql != expected {
+		t.Errorf("expected %s, got %s", expected, uql)
+	}
+
+	// move the token to _uuid 42 as if pages were fetched
+	token, err := nodes.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := map[string]interface{}{}
+	if err = json.Unmarshal(data, &content); err != nil {
+		t.Fatal(err)
+	}
+	content["a"] = 42
+	data, _ = json.Marshal(content)
+	token = base64.RawURLEncoding.EncodeToString(data)
+
+	resumed := client.NewNodePaginator("account", uqlbuilder.Gt("age", 18), 100, nil)
+	if err = resumed.Resume(token); err != nil {
+		t.Fatal(err)
+	}
+	uql, _ = resumed.Uql()
+	if expected := `find().nodes({@account && _uuid > 42 && age > 18}) as nodes order by nodes._uuid limit 100 return nodes{*}`; uql != expected {
+		t.Errorf("expected %s, got %s", expected, uql)
+	}
+	if !resumed.HasNext() {
+		t.Error("expected the resumed paginator to have next page")
+	}
+
+	mismatched := []interface {
+		Resume(token string) error
+	}{
+		client.NewNodePaginator("account", uqlbuilder.Gt("age", 20), 100, nil),
+		client.NewNodePaginator("card", uqlbuilder.Gt("age", 18), 100, nil),
+		client.NewEdgePaginator("account", uqlbuilder.Gt("age", 18), 100, nil),
+		client.NewNodePaginator("account", uqlbuilder.Gt("age", 18), 100, &configuration.RequestConfig{GraphName: "g2"}),
+	}
+	for i, p := range mismatched {
+		if err = p.Resume(token); err == nil {
+			t.Errorf("case %d: expected the token not to match", i)
+		}
+	}
+	if err = resumed.Resume("not a token"); err == nil {
+		t.Error("expected an invalid token to fail")
+	}
+}
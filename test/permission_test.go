@@ -0,0 +1,138 @@
+package test
+
+import (
+	"context"
+	"errors"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAuthenticateServer an in-process server of the Authenticate rpc, the types in denied are denied
+type fakeAuthenticateServer struct {
+	fakeControlsServer
+	lock     sync.Mutex
+	denied   map[ultipa.AuthenticateType]bool
+	requests []*ultipa.AuthenticateRequest
+}
+
+func (s *fakeAuthenticateServer) Authenticate(ctx context.Context, in *ultipa.AuthenticateRequest) (*ultipa.AuthenticateReply, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.requests = append(s.requests, in)
+	if s.denied[in.Type] {
+		return &ultipa.AuthenticateReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_PERMISSION_DENIED, Msg: "no INSERT privilege"}}, nil
+	}
+	return &ultipa.AuthenticateReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}}, nil
+}
+
+func TestCanI(t *testing.T) {
+	fake := &fakeAuthenticateServer{denied: map[ultipa.AuthenticateType]bool{
+		ultipa.AuthenticateType_PERMISSION_TYPE_INSERTNODES: true,
+	}}
+	client := newFakeServerClient(t, fake)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		allowed, err := client.CanI(ctx, api.OperationUQL, "find().nodes() as n return n", nil)
+		if err != nil || !allowed {
+			t.Fatalf("expected uql to be allowed, got %v %v", allowed, err)
+		}
+	}
+	if len(fake.requests) != 1 || fake.requests[0].Uql != "find().nodes() as n return n" {
+		t.Errorf("expected the second CanI to be cached, got %v", fake.requests)
+	}
+
+	allowed, err := client.CanI(ctx, api.OperationInsertNodes, "", nil)
+	if err != nil || allowed {
+		t.Errorf("expected InsertNodes to be denied, got %v %v", allowed, err)
+	}
+	if fake.requests[1].Type != ultipa.AuthenticateType_PERMISSION_TYPE_INSERTNODES {
+		t.Errorf("unexpected authenticate type %v", fake.requests[1].Type)
+	}
+
+	// the preflight fails before sending the nodes
+	_, err = client.InsertNodesBatch(&ultipa.EntityTable{}, &configuration.InsertRequestConfig{
+		RequestConfig: &configuration.RequestConfig{GraphName: "amz", Preflight: true},
+	})
+	var denied *utils.PermissionDeniedError
+	if !errors.Is(err, utils.ErrPermissionDenied) || !errors.As(err, &denied) {
+		t.Fatalf("expected a permission denied error, got %v", err)
+	}
+	if denied.Operation != "InsertNodes" || denied.Graph != "amz" || denied.Message != "no INSERT privilege" {
+		t.Errorf("unexpected error %+v", denied)
+	}
+
+	client.Permissions.Clear()
+	if allowed, err = client.CanI(ctx, api.OperationUQL, "find().nodes() as n return n", nil); err != nil || !allowed {
+		t.Errorf("expected uql to be allowed, got %v %v", allowed, err)
+	}
+	if len(fake.requests) != 4 {
+		t.Errorf("expected the cache to be cleared, got %d requests", len(fake.requests))
+	}
+
+	if _, err = client.CanI(ctx, api.Operation("Unknown"), "", nil); err == nil {
+		t.Error("expected an error of unknown operation")
+	}
+}
+
+func TestPreflightBeforeQueryCache(t *testing.T) {
+	fake := &fakeAuthenticateServer{denied: map[ultipa.AuthenticateType]bool{
+		ultipa.AuthenticateType_PERMISSION_TYPE_UQL: true,
+	}}
+	client := newFakeServerClient(t, fake)
+
+	uql := "find().nodes() as n return n"
+	client.QueryCache = api.NewUqlCache(10, time.Minute)
+	client.QueryCache.Set("amz", uql, &http.UQLResponse{Status: &http.Status{Code: ultipa.ErrorCode_SUCCESS}})
+
+	_, err := client.UQL(uql, &configuration.RequestConfig{GraphName: "amz", Preflight: true})
+	if !errors.Is(err, utils.ErrPermissionDenied) {
+		t.Fatalf("expected the cached uql to be denied, got %v", err)
+	}
+	if resp, err := client.UQL(uql, &configuration.RequestConfig{GraphName: "amz"}); err != nil || resp == nil {
+		t.Errorf("expected the cached response without preflight, got %v %v", resp, err)
+	}
+}
+
+func TestPermissionCacheSize(t *testing.T) {
+	fake := &fakeAuthenticateServer{}
+	client := newFakeServerClient(t, fake)
+	client.Permissions = api.NewPermissionCache(time.Minute)
+	client.Permissions.Size = 2
+	ctx := context.Background()
+
+	for _, uql := range []string{"find().nodes(1) return nodes", "find().nodes(2) return nodes", "find().nodes(3) return nodes"} {
+		if _, err := client.CanI(ctx, api.OperationUQL, uql, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if client.Permissions.Len() != 2 {
+		t.Errorf("expected 2 cached results, got %d", client.Permissions.Len())
+	}
+
+	// the oldest result is evicted
+	if _, err := client.CanI(ctx, api.OperationUQL, "find().nodes(1) return nodes", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.requests) != 4 {
+		t.Errorf("expected the oldest result to be evicted, got %d requests", len(fake.requests))
+	}
+
+	// expired results are swept before evicting
+	client.Permissions = api.NewPermissionCache(time.Millisecond)
+	client.Permissions.Size = 2
+	for _, uql := range []string{"find().nodes(1) return nodes", "find().nodes(2) return nodes"} {
+		client.CanI(ctx, api.OperationUQL, uql, nil)
+	}
+	time.Sleep(5 * time.Millisecond)
+	client.CanI(ctx, api.OperationUQL, "find().nodes(3) return nodes", nil)
+	if client.Permissions.Len() != 1 {
+		t.Errorf("expected the expired results to be swept, got %d", client.Permissions.Len())
+	}
+}
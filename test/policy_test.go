@@ -0,0 +1,109 @@
+package test
+
+import (
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"reflect"
+	"testing"
+)
+
+func newStringTable(t *testing.T, name string, headers []string, rows [][]string) *http.DataItem {
+	table := &ultipa.Table{TableName: name}
+	for _, header := range headers {
+		table.Headers = append(table.Headers, &ultipa.Header{PropertyName: header, PropertyType: ultipa.PropertyType_STRING})
+	}
+	for _, row := range rows {
+		values := [][]byte{}
+		for _, v := range row {
+			values = append(values, mustBytes(t, v))
+		}
+		table.TableRows = append(table.TableRows, &ultipa.TableRow{Values: values})
+	}
+	return &http.DataItem{Alias: name, Type: ultipa.ResultType_RESULT_TYPE_TABLE, Data: table}
+}
+
+func TestAsPolicies(t *testing.T) {
+	item := newStringTable(t, http.RESP_POLICY_KEY,
+		[]string{"name", "graphPrivileges", "systemPrivileges", "propertyPrivileges", "policies"},
+		[][]string{
+			{"operator", `{"default":["FIND"]}`, `["SHOW_GRAPH"]`, `{"node":{"read":[["default","account","name"]],"write":[],"deny":[["*","*","password"]]},"edge":{"read":[],"write":[],"deny":[]}}`, `["reader"]`},
+			{"reader", "", "", "", ""},
+		})
+
+	policies, err := item.AsPolicies()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 2 {
+		t.Fatalf("expected 2 policies, got %d", len(policies))
+	}
+	operator := policies[0]
+	if operator.Name != "operator" || !reflect.DeepEqual(operator.GraphPrivileges["default"], []string{"FIND"}) ||
+		!reflect.DeepEqual(operator.SystemPrivileges, []string{"SHOW_GRAPH"}) || !reflect.DeepEqual(operator.Policies, []string{"reader"}) {
+		t.Errorf("unexpected policy %+v", operator)
+	}
+	if !reflect.DeepEqual(operator.PropertyPrivileges.Node.Read, [][]string{{"default", "account", "name"}}) ||
+		!reflect.DeepEqual(operator.PropertyPrivileges.Node.Deny, [][]string{{"*", "*", "password"}}) {
+		t.Errorf("unexpected property privileges %+v", operator.PropertyPrivileges)
+	}
+	if !policies[1].PrivilegeSet().IsEmpty() {
+		t.Errorf("expected empty policy, got %+v", policies[1])
+	}
+
+	privileges, err := newStringTable(t, http.RESP_PRIVILEGE_KEY,
+		[]string{"graphPrivileges", "systemPrivileges", "propertyPrivileges"},
+		[][]string{{`["FIND","INSERT"]`, `["SHOW_GRAPH"]`, `["READ","WRITE","DENY"]`}}).AsPrivileges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(privileges) != 6 || *privileges[0] != (structs.Privilege{Name: "FIND", Level: structs.PrivilegeLevelGraph}) ||
+		*privileges[2] != (structs.Privilege{Name: "SHOW_GRAPH", Level: structs.PrivilegeLevelSystem}) ||
+		*privileges[5] != (structs.Privilege{Name: "DENY", Level: structs.PrivilegeLevelProperty}) {
+		t.Errorf("unexpected privileges %v", privileges)
+	}
+}
+
+func TestDiffPrivilegeUql(t *testing.T) {
+	current := &structs.User{
+		Username:         "bob",
+		GraphPrivileges:  structs.GraphPrivileges{"default": {"FIND", "INSERT"}, "old": {"FIND"}},
+		SystemPrivileges: []string{"SHOW_GRAPH"},
+		PropertyPrivileges: &structs.PropertyPrivileges{
+			Node: structs.PropertyPrivilege{Deny: [][]string{{"*", "*", "password"}}},
+		},
+		Policies: []string{"reader"},
+	}
+	desired := &structs.User{
+		Username:         "bob",
+		GraphPrivileges:  structs.GraphPrivileges{"default": {"FIND", "UPDATE"}},
+		SystemPrivileges: []string{"SHOW_GRAPH"},
+		PropertyPrivileges: &structs.PropertyPrivileges{
+			Node: structs.PropertyPrivilege{Deny: [][]string{{"*", "*", "password"}}, Read: [][]string{{"default", "account", "name"}}},
+		},
+	}
+
+	uqls, err := api.DiffUserUql(current, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		`revoke().user("bob").params({graph_privileges: {"default": ["INSERT"], "old": ["FIND"]}})`,
+		`grant().user("bob").params({graph_privileges: {"default": ["UPDATE"]}, property_privileges: {"node": {"read": [["default", "account", "name"]], "write": [], "deny": []}, "edge": {"read": [], "write": [], "deny": []}}})`,
+	}
+	if !reflect.DeepEqual(uqls, expected) {
+		t.Errorf("expected %q, got %q", expected, uqls)
+	}
+
+	// nil fields of desired are kept, so nothing to do
+	uqls, err = api.DiffPolicyUql(&structs.Policy{Name: "reader", Policies: []string{"a"}}, &structs.Policy{Name: "reader"})
+	if err != nil || len(uqls) != 0 {
+		t.Errorf("expected no uql, got %q, %v", uqls, err)
+	}
+
+	uqls, err = api.DiffPolicyUql(nil, &structs.Policy{Name: "operator", Policies: []string{"reader"}})
+	if err != nil || !reflect.DeepEqual(uqls, []string{`grant().policy("operator").params({policies: ["reader"]})`}) {
+		t.Errorf("unexpected uql %q, %v", uqls, err)
+	}
+}
@@ -0,0 +1,152 @@
+package test
+
+import (
+	"context"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeProcessServer runs the uql of Uql until it is cancelled, and lists it by top() with another process running the same uql,
+// the first top() fails if topFails
+type fakeProcessServer struct {
+	fakeControlsServer
+	t        *testing.T
+	topFails bool
+
+	lock    sync.Mutex
+	started bool
+	tops    int
+	killed  []string
+}
+
+func (s *fakeProcessServer) UqlEx(in *ultipa.UqlRequest, stream ultipa.UltipaControls_UqlExServer) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if strings.HasPrefix(in.Uql, "kill(") {
+		s.killed = append(s.killed, in.Uql)
+		return stream.Send(&ultipa.UqlReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}})
+	}
+	if in.Uql != "top()" {
+		return stream.Send(&ultipa.UqlReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}})
+	}
+	s.tops++
+	if s.topFails && s.tops == 1 {
+		return stream.Send(&ultipa.UqlReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_FAILED, Msg: "top() failed"}})
+	}
+	table := &ultipa.Table{
+		TableName: http.RESP_TOP_KEY,
+		Headers: []*ultipa.Header{
+			{PropertyName: "process_id", PropertyType: ultipa.PropertyType_STRING},
+			{PropertyName: "process_uql", PropertyType: ultipa.PropertyType_STRING},
+			{PropertyName: "duration", PropertyType: ultipa.PropertyType_STRING},
+		},
+	}
+	ids := []string{"p_other"}
+	if s.started {
+		ids = append(ids, "p_new")
+	}
+	for _, id := range ids {
+		table.TableRows = append(table.TableRows, &ultipa.TableRow{Values: [][]byte{
+			mustBytes(s.t, id), mustBytes(s.t, "find().nodes() as n return n"), mustBytes(s.t, "1"),
+		}})
+	}
+	return stream.Send(&ultipa.UqlReply{
+		Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS},
+		Alias:  []*ultipa.ResultAlias{{Alias: http.RESP_TOP_KEY, ResultType: ultipa.ResultType_RESULT_TYPE_TABLE}},
+		Tables: []*ultipa.Table{table},
+	})
+}
+
+func (s *fakeProcessServer) rpcsServer() ultipa.UltipaRpcsServer {
+	return &fakeProcessRpcsServer{server: s}
+}
+
+type fakeProcessRpcsServer struct {
+	ultipa.UnimplementedUltipaRpcsServer
+	server *fakeProcessServer
+}
+
+func (s *fakeProcessRpcsServer) Uql(in *ultipa.UqlRequest, stream ultipa.UltipaRpcs_UqlServer) error {
+	s.server.lock.Lock()
+	s.server.started = true
+	s.server.lock.Unlock()
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+func (s *fakeProcessServer) killedUql() ([]string, int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string{}, s.killed...), s.tops
+}
+
+func TestProcesses(t *testing.T) {
+	table := &ultipa.Table{
+		TableName: http.RESP_TOP_KEY,
+		Headers: []*ultipa.Header{
+			{PropertyName: "process_id", PropertyType: ultipa.PropertyType_STRING},
+			{PropertyName: "process_uql", PropertyType: ultipa.PropertyType_STRING},
+			{PropertyName: "duration", PropertyType: ultipa.PropertyType_STRING},
+		},
+	}
+	for _, row := range [][]string{
+		{"a_1", "find().nodes() as n return n", "120"},
+		{"a_2", "khop().src({_id == \"A\"}).depth(5) as n return count(n)", "30"},
+		{"a_3", "find().nodes()  as n\nreturn n", "5"},
+	} {
+		table.TableRows = append(table.TableRows, &ultipa.TableRow{Values: [][]byte{
+			mustBytes(t, row[0]), mustBytes(t, row[1]), mustBytes(t, row[2]),
+		}})
+	}
+	item := &http.DataItem{Alias: http.RESP_TOP_KEY, Type: ultipa.ResultType_RESULT_TYPE_TABLE, Data: table}
+
+	processes, err := item.AsProcesses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processes) != 3 || processes[1].Id != "a_2" || processes[1].Duration != "30" || processes[1].Status != "" {
+		t.Fatalf("unexpected processes %+v", processes)
+	}
+
+	matched := api.FilterProcessesByUql(processes, "find().nodes() as n return n")
+	if len(matched) != 2 || matched[0].Id != "a_1" || matched[1].Id != "a_3" {
+		t.Errorf("unexpected matched processes %+v", matched)
+	}
+	if matched = api.FilterProcessesByUql(processes, "find().edges() as e return e"); len(matched) != 0 {
+		t.Errorf("expected no process matched, got %+v", matched)
+	}
+}
+
+func TestKillOnCancel(t *testing.T) {
+	for _, topFails := range []bool{false, true} {
+		fake := &fakeProcessServer{t: t, topFails: topFails}
+		client := newFakeServerClient(t, fake)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		_, err := client.UQL("find().nodes() as n return n", &configuration.RequestConfig{Context: ctx, KillOnCancel: true})
+		cancel()
+		if err == nil {
+			t.Fatal("expected the cancelled uql to fail")
+		}
+
+		// the process is killed in background after cancelling
+		time.Sleep(200 * time.Millisecond)
+		killed, tops := fake.killedUql()
+		if topFails {
+			// kill on cancel is turned off if the processes can not be listed before sending
+			if len(killed) != 0 || tops != 1 {
+				t.Errorf("expected nothing to be killed after top() failed, got %v, %d top()", killed, tops)
+			}
+			continue
+		}
+		if len(killed) != 1 || killed[0] != `kill("p_new")` {
+			t.Errorf("expected only the new process to be killed, got %v", killed)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package test
+
+import (
+	"context"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"google.golang.org/grpc"
+	"io"
+	"testing"
+)
+
+// fakeUqlStream replays replies as an uql stream
+type fakeUqlStream struct {
+	grpc.ClientStream
+	replies []*ultipa.UqlReply
+	recv    int
+	closed  bool
+}
+
+func (s *fakeUqlStream) Recv() (*ultipa.UqlReply, error) {
+	if s.closed {
+		return nil, context.Canceled
+	}
+	if s.recv >= len(s.replies) {
+		return nil, io.EOF
+	}
+	s.recv++
+	return s.replies[s.recv-1], nil
+}
+
+func (s *fakeUqlStream) CloseSend() error {
+	s.closed = true
+	return nil
+}
+
+func mustBytes(t *testing.T, value interface{}) []byte {
+	bs, err := utils.ConvertInterfaceToBytes(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
+
+func nodeChunk(t *testing.T, withSchema bool, ids ...string) *ultipa.UqlReply {
+	table := &ultipa.EntityTable{}
+	if withSchema {
+		table.Schemas = []*ultipa.Schema{{SchemaName: "user", Properties: []*ultipa.Property{
+			{PropertyName: "name", PropertyType: ultipa.PropertyType_STRING},
+			{PropertyName: "age", PropertyType: ultipa.PropertyType_INT32},
+		}}}
+	}
+	for i, id := range ids {
+		table.EntityRows = append(table.EntityRows, &ultipa.EntityRow{
+			Id:         id,
+			Uuid:       uint64(i + 1),
+			SchemaName: "user",
+			Values:     [][]byte{mustBytes(t, "name-"+id), mustBytes(t, int32(i))},
+		})
+	}
+	return &ultipa.UqlReply{
+		Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS},
+		Alias: []*ultipa.ResultAlias{
+			{Alias: "n", ResultType: ultipa.ResultType_RESULT_TYPE_NODE},
+			{Alias: "t", ResultType: ultipa.ResultType_RESULT_TYPE_TABLE},
+		},
+		Nodes: []*ultipa.NodeAlias{{Alias: "n", NodeTable: table}},
+	}
+}
+
+func TestRowsNodes(t *testing.T) {
+	stream := &fakeUqlStream{replies: []*ultipa.UqlReply{
+		nodeChunk(t, true, "a", "b"),
+		nodeChunk(t, false),
+		nodeChunk(t, false, "c"),
+	}}
+	cancelled := false
+	rows, err := http.NewRows(stream, func() { cancelled = true })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var node structs.Node
+		if err := rows.Scan(&node); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, node.ID)
+		var user struct {
+			ID  string `ultipa:"_id"`
+			Age int64  `ultipa:"age"`
+		}
+		if err := rows.Scan(&user); err != nil || user.ID != node.ID {
+			t.Errorf("unexpected user %+v, err: %v", user, err)
+		}
+		if node.Values.Get("name") != "name-"+node.ID {
+			t.Errorf("unexpected name %v of node %s", node.Values.Get("name"), node.ID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 || ids[0] != "a" || ids[1] != "b" || ids[2] != "c" {
+		t.Errorf("expected nodes [a b c], got %v", ids)
+	}
+	if !cancelled {
+		t.Error("expected request to be cancelled after the stream ends")
+	}
+}
+
+func TestRowsTableAndClose(t *testing.T) {
+	chunk := func(values ...int32) *ultipa.UqlReply {
+		table := &ultipa.Table{TableName: "t", Headers: []*ultipa.Header{
+			{PropertyName: "id", PropertyType: ultipa.PropertyType_INT32},
+			{PropertyName: "name", PropertyType: ultipa.PropertyType_STRING},
+		}}
+		for _, v := range values {
+			table.TableRows = append(table.TableRows, &ultipa.TableRow{Values: [][]byte{mustBytes(t, v), mustBytes(t, "x")}})
+		}
+		return &ultipa.UqlReply{
+			Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS},
+			Alias: []*ultipa.ResultAlias{
+				{Alias: "n", ResultType: ultipa.ResultType_RESULT_TYPE_NODE},
+				{Alias: "t", ResultType: ultipa.ResultType_RESULT_TYPE_TABLE},
+			},
+			Tables: []*ultipa.Table{table},
+		}
+	}
+	stream := &fakeUqlStream{replies: []*ultipa.UqlReply{chunk(1, 2), chunk(3, 4)}}
+	rows, err := http.NewRows(stream, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rows.Select("t"); err != nil {
+		t.Fatal(err)
+	}
+
+	var sum int64
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatal(err)
+		}
+		sum += id
+		if id == 3 {
+			if err := rows.Close(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if sum != 6 {
+		t.Errorf("expected rows to stop after Close, sum = %d", sum)
+	}
+	if !stream.closed || stream.recv != 2 {
+		t.Errorf("expected the stream to be closed after 2 chunks, closed: %t, recv: %d", stream.closed, stream.recv)
+	}
+	if err := rows.Select("n"); err == nil {
+		t.Error("expected Select after Next to fail")
+	}
+}
+
+func TestRowsFailure(t *testing.T) {
+	failed := &ultipa.UqlReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_FAILED, Msg: "syntax error"}}
+	rows, err := http.NewRows(&fakeUqlStream{replies: []*ultipa.UqlReply{failed}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows.Next() || rows.Err() == nil {
+		t.Error("expected failed status to stop rows with an error")
+	}
+
+	rows, err = http.NewRows(&fakeUqlStream{replies: []*ultipa.UqlReply{nodeChunk(t, true, "a"), failed}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for rows.Next() {
+		count++
+		var table structs.Table
+		if err := rows.Scan(&table); err == nil {
+			t.Error("expected scanning a node into a table to fail")
+		}
+	}
+	if count != 1 || rows.Err() == nil {
+		t.Errorf("expected 1 row and an error, got %d rows, err: %v", count, rows.Err())
+	}
+}
@@ -0,0 +1,183 @@
+package test
+
+import (
+	"bufio"
+	"encoding/json"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/configuration"
+	"github.com/ultipa/ultipa-go-sdk/sdk/connection"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSlowQueryLog(t *testing.T) {
+	var records []*api.SlowQueryRecord
+	slowLog := api.NewSlowQueryLog(100*time.Millisecond, api.SlowQueryFunc(func(record *api.SlowQueryRecord) {
+		records = append(records, record)
+	}))
+
+	cases := []struct {
+		latency   time.Duration
+		totalCost int
+		force     bool
+		recorded  bool
+	}{
+		{10 * time.Millisecond, 5, false, false},
+		{200 * time.Millisecond, 5, false, true},
+		{10 * time.Millisecond, 150, false, true},
+		{10 * time.Millisecond, 5, true, true},
+	}
+	for i, c := range cases {
+		before := len(records)
+		err := slowLog.Log(&api.SlowQueryRecord{Latency: c.latency, TotalCost: c.totalCost}, c.force)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recorded := len(records) > before; recorded != c.recorded {
+			t.Errorf("case %d: expected recorded = %t", i, c.recorded)
+		}
+	}
+}
+
+func TestSlowQueryRecord(t *testing.T) {
+	resp := jsonTestResponse(t)
+	conn := &connection.Connection{Host: "10.0.0.1:60061"}
+	record := api.NewSlowQueryRecord("find().nodes() as n return n", "g1", conn, time.Now().Add(-time.Second), resp, nil)
+
+	if record.Host != conn.Host || record.Graph != "g1" || record.TotalCost != 3 || record.EngineCost != 1 {
+		t.Errorf("unexpected record %+v", record)
+	}
+	if record.Latency < time.Second || record.Code != "SUCCESS" || record.Error != "" {
+		t.Errorf("unexpected latency %v, code %s or error %s", record.Latency, record.Code, record.Error)
+	}
+	expectedRows := map[string]int{"n": 1, "t": 1, "a": 2, "l": 2}
+	for alias, count := range expectedRows {
+		if record.Rows[alias] != count {
+			t.Errorf("expected %d rows of %s, got %d", count, alias, record.Rows[alias])
+		}
+	}
+}
+
+func TestStreamSummary(t *testing.T) {
+	var summaries []*http.StreamSummary
+	onClose := func(summary *http.StreamSummary) {
+		summaries = append(summaries, summary)
+	}
+
+	rows, err := http.NewRows(&fakeUqlStream{replies: []*ultipa.UqlReply{
+		nodeChunk(t, true, "a", "b"),
+		nodeChunk(t, false, "c"),
+	}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.OnClose(onClose)
+	for rows.Next() {
+	}
+	if len(summaries) != 1 || summaries[0].RowCounts["n"] != 3 || summaries[0].Err != nil {
+		t.Fatalf("expected a summary of 3 nodes when rows are drained, got %+v", summaries)
+	}
+	_ = rows.Close()
+	if len(summaries) != 1 {
+		t.Error("expected OnClose to be called once")
+	}
+
+	stream, err := http.NewUQLResponseStream(&fakeUqlStream{replies: []*ultipa.UqlReply{
+		nodeChunk(t, true, "a"),
+		nodeChunk(t, false, "b", "c"),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		_, err = stream.Recv(true)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	stream.OnClose(onClose)
+	if len(summaries) != 2 || summaries[1].RowCounts["n"] != 3 || summaries[1].Status.Code != ultipa.ErrorCode_SUCCESS {
+		t.Fatalf("expected OnClose of a closed stream to be called at once, got %+v", summaries)
+	}
+}
+
+func TestSlowQueryFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slow.log")
+	sink, err := api.NewSlowQueryFileSink(path, 200, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		err = sink.Record(&api.SlowQueryRecord{Uql: "find().nodes() as n return n", Graph: "g1", Latency: time.Second})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 200 {
+			t.Errorf("expected %s to be rotated before 200 bytes, got %d", name, info.Size())
+		}
+	}
+	if _, err = os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("expected at most 2 backups")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record api.SlowQueryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record.Graph != "g1" {
+			t.Errorf("unexpected line %s, err: %v", scanner.Text(), err)
+		}
+	}
+}
+
+func TestSlowQueryLogOfClient(t *testing.T) {
+	// records go to the sdk log if no file is set
+	client := newFakeServerClientWithConfig(t, &fakeUqlServer{}, &configuration.UltipaConfig{
+		Username:           "root",
+		Password:           "root",
+		SlowQueryThreshold: 500,
+	})
+	if _, ok := client.SlowQueryLog.Sink.(api.SlowQueryLoggerSink); !ok {
+		t.Errorf("expected the logger sink, got %T", client.SlowQueryLog.Sink)
+	}
+	client.Close()
+
+	// the log file is closed with the client
+	client = newFakeServerClientWithConfig(t, &fakeUqlServer{}, &configuration.UltipaConfig{
+		Username:           "root",
+		Password:           "root",
+		SlowQueryThreshold: 500,
+		SlowQueryLogFile:   filepath.Join(t.TempDir(), "slow.log"),
+	})
+	sink, ok := client.SlowQueryLog.Sink.(*api.SlowQueryFileSink)
+	if !ok {
+		t.Fatalf("expected the file sink, got %T", client.SlowQueryLog.Sink)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Record(&api.SlowQueryRecord{Uql: "find().nodes() as n return n"}); err == nil {
+		t.Error("expected the log file to be closed with the client")
+	}
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"testing"
+)
+
+func taskItem(t *testing.T, rows ...[3]string) *http.DataItem {
+	table := &ultipa.Table{
+		TableName: http.RESP_TASK_KEY,
+		Headers: []*ultipa.Header{
+			{PropertyName: "task_info", PropertyType: ultipa.PropertyType_STRING},
+			{PropertyName: "param", PropertyType: ultipa.PropertyType_STRING},
+			{PropertyName: "result", PropertyType: ultipa.PropertyType_STRING},
+		},
+	}
+	for _, row := range rows {
+		table.TableRows = append(table.TableRows, &ultipa.TableRow{Values: [][]byte{
+			mustBytes(t, row[0]), mustBytes(t, row[1]), mustBytes(t, row[2]),
+		}})
+	}
+	return &http.DataItem{Alias: http.RESP_TASK_KEY, Type: ultipa.ResultType_RESULT_TYPE_TABLE, Data: table}
+}
+
+func TestAsTasks(t *testing.T) {
+	item := taskItem(t,
+		[3]string{
+			`{"task_id":12,"server_id":"1","algo_name":"degree","start_time":1600000000,"writing_start_time":1600000005,"end_time":1600000010,"time_cost":10,"engine_cost":4,"TASK_STATUS":3}`,
+			`{"order":"desc"}`,
+			`{"degree_file":"degree_all_1,degree_all_2","total":100}`,
+		},
+		[3]string{`{"task_id":"13","algo_name":"louvain","TASK_STATUS":1}`, ``, `{}`},
+	)
+
+	tasks, err := item.AsTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(tasks))
+	}
+
+	done := tasks[0]
+	if done.Id != "12" || done.Algo != "degree" || done.Status != ultipa.TASK_STATUS_TASK_DONE || done.StatusName() != "DONE" {
+		t.Errorf("unexpected task %+v", done)
+	}
+	if done.StartTime.Unix() != 1600000000 || done.EndTime.Unix() != 1600000010 || done.TimeCost != 10 || done.EngineCost != 4 {
+		t.Errorf("unexpected timings %v %v %d %d", done.StartTime, done.EndTime, done.TimeCost, done.EngineCost)
+	}
+	if len(done.ResultFiles) != 2 || done.ResultFiles[0] != "degree_all_1" || done.ResultFiles[1] != "degree_all_2" {
+		t.Errorf("unexpected result files %v", done.ResultFiles)
+	}
+	if done.Params["order"] != "desc" || !done.IsFinished() {
+		t.Errorf("unexpected params %v or finished %t", done.Params, done.IsFinished())
+	}
+
+	computing := tasks[1]
+	if computing.Id != "13" || computing.IsFinished() || !computing.StartTime.IsZero() || len(computing.ResultFiles) != 0 {
+		t.Errorf("unexpected task %+v", computing)
+	}
+
+	if _, err := structs.NewTask(`{"task_id":`, "", ""); err == nil {
+		t.Error("expected an invalid task_info to fail")
+	}
+
+	item.Data.(*ultipa.Table).TableName = "_algoList"
+	if _, err := item.AsTasks(); err == nil {
+		t.Error("expected a table other than _task to fail")
+	}
+}
+
+func TestTaskManagerOperate(t *testing.T) {
+	fake := &fakeUqlServer{}
+	manager := newFakeServerClient(t, fake).TaskManager()
+
+	cases := []struct {
+		operate func(taskId string) error
+		expect  string
+	}{
+		{func(taskId string) error { return manager.Stop(taskId, nil) }, "stop().task(12)"},
+		{func(taskId string) error { return manager.Pause(taskId, nil) }, "pause().task(12)"},
+		{func(taskId string) error { return manager.Resume(taskId, nil) }, "resume().task(12)"},
+	}
+	for _, c := range cases {
+		if err := c.operate("12"); err != nil {
+			t.Fatal(err)
+		}
+		if fake.lastUql() != c.expect {
+			t.Errorf("expected %s, got %s", c.expect, fake.lastUql())
+		}
+	}
+	if err := manager.Stop("12) || drop().graph(g", nil); err == nil {
+		t.Error("expected an error of invalid task id")
+	}
+}
@@ -0,0 +1,132 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeUploaderServer an in-process server of the Uploader rpcs, the first stream of failFile is aborted after a chunk
+type fakeUploaderServer struct {
+	fakeControlsServer
+	lock     sync.Mutex
+	failFile string
+	failed   bool
+	files    map[string][]byte
+	requests []*ultipa.UploaderRequest
+	created  *ultipa.CreateGraphByUploaderRequest
+}
+
+func (s *fakeUploaderServer) Uploader(stream ultipa.UltipaControls_UploaderServer) error {
+	var content bytes.Buffer
+	var name string
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		s.lock.Lock()
+		s.requests = append(s.requests, req)
+		abort := req.FileName == s.failFile && !s.failed
+		if abort {
+			s.failed = true
+		}
+		s.lock.Unlock()
+		if abort {
+			return status.Error(codes.Unavailable, "connection reset")
+		}
+		name = req.FileName
+		content.Write(req.Chunk)
+	}
+	s.lock.Lock()
+	s.files[name] = content.Bytes()
+	s.lock.Unlock()
+	return stream.SendAndClose(&ultipa.UploaderReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}})
+}
+
+func (s *fakeUploaderServer) CreateGraphByUploader(ctx context.Context, in *ultipa.CreateGraphByUploaderRequest) (*ultipa.CreateGraphByUploaderReply, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.created = in
+	return &ultipa.CreateGraphByUploaderReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}}, nil
+}
+
+func TestUploadGraphFiles(t *testing.T) {
+	fake := &fakeUploaderServer{failFile: "transfer.csv", files: map[string][]byte{}}
+	client := newFakeServerClient(t, fake)
+
+	dir := t.TempDir()
+	accounts := []byte("_id,name\nA,Alice\nB,Bob\n")
+	transfers := []byte("_from,_to,amount\nA,B,100\nB,A,20\n")
+	for name, content := range map[string][]byte{"account.csv": accounts, "transfer.csv": transfers} {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var progresses []api.UploadProgress
+	result, err := client.UploadGraphFiles(context.Background(), "amz", []*api.GraphFile{
+		{DBType: ultipa.DBType_DBNODE, Path: filepath.Join(dir, "account.csv")},
+		{DBType: ultipa.DBType_DBEDGE, Path: filepath.Join(dir, "transfer.csv")},
+	}, &api.UploadGraphOptions{
+		GraphDescription: "uploaded",
+		ChunkSize:        8,
+		RetryInterval:    time.Millisecond,
+		OnProgress: func(progress *api.UploadProgress) {
+			progresses = append(progresses, *progress)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Created || result.Retries != 1 || len(result.Uploaded) != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if !bytes.Equal(fake.files["account.csv"], accounts) || !bytes.Equal(fake.files["transfer.csv"], transfers) {
+		t.Errorf("unexpected files uploaded %q", fake.files)
+	}
+	if fake.created == nil || fake.created.GraphName != "amz" || fake.created.GraphDescription != "uploaded" {
+		t.Errorf("unexpected create graph request %v", fake.created)
+	}
+	for _, req := range fake.requests {
+		if req.GraphName != "amz" || req.TotalFileCounts != 2 {
+			t.Errorf("unexpected request %v", req)
+		}
+		if req.FileName == "transfer.csv" && (req.DbType != ultipa.DBType_DBEDGE || req.FileSize != uint64(len(transfers))) {
+			t.Errorf("unexpected request %v", req)
+		}
+	}
+
+	last := progresses[len(progresses)-1]
+	if last.TotalSent != last.TotalSize || last.TotalSize != uint64(len(accounts)+len(transfers)) || last.Attempt != 2 {
+		t.Errorf("unexpected last progress %+v", last)
+	}
+
+	// resume skips the files uploaded
+	fake.requests = nil
+	result, err = client.UploadGraphFiles(context.Background(), "amz", []*api.GraphFile{
+		{DBType: ultipa.DBType_DBNODE, Path: filepath.Join(dir, "account.csv")},
+		{DBType: ultipa.DBType_DBEDGE, Path: filepath.Join(dir, "transfer.csv")},
+	}, &api.UploadGraphOptions{Uploaded: []string{"account.csv"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, req := range fake.requests {
+		if req.FileName != "transfer.csv" {
+			t.Errorf("expected account.csv to be skipped, got %v", req)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"github.com/ultipa/ultipa-go-sdk/sdk/types"
+	"github.com/ultipa/ultipa-go-sdk/sdk/uqlbuilder"
+	"testing"
+)
+
+func TestUqlBuilder(t *testing.T) {
+	cases := []struct {
+		name     string
+		builder  *uqlbuilder.Builder
+		expected string
+	}{
+		{
+			name:     "find nodes",
+			builder:  uqlbuilder.New().FindNodes(nil).As("n").Return("n{*}").Limit(10),
+			expected: `find().nodes() as n return n{*} limit 10`,
+		},
+		{
+			name: "find nodes with filters",
+			builder: uqlbuilder.New().
+				FindNodes(uqlbuilder.And(uqlbuilder.Schema("account"), uqlbuilder.Gt("age", 18), uqlbuilder.Or(uqlbuilder.Eq("city", `Bei"jing`), uqlbuilder.Eq("city", "Shanghai")))).
+				As("n").OrderBy("n.age desc").Skip(5).Limit(10).Return("n{*}"),
+			expected: `find().nodes({@account && age > 18 && (city == "Bei\"jing" || city == "Shanghai")}) as n order by n.age desc skip 5 limit 10 return n{*}`,
+		},
+		{
+			name:     "find edges by escaped schema",
+			builder:  uqlbuilder.New().FindEdges(uqlbuilder.Schema("transfer to")).As("e").Return("e{*}"),
+			expected: "find().edges({@`transfer to`}) as e return e{*}",
+		},
+		{
+			name:     "find nodes by ids and not",
+			builder:  uqlbuilder.New().FindNodes(uqlbuilder.And(uqlbuilder.ID("a", "b"), uqlbuilder.Not(uqlbuilder.Eq("n.my name", nil)))).As("n").Return("n"),
+			expected: "find().nodes({_id in [\"a\", \"b\"] && !(n.`my name` == null)}) as n return n",
+		},
+		{
+			name:     "expr filter",
+			builder:  uqlbuilder.New().FindNodes(uqlbuilder.Expr("@card && balance > $min", map[string]interface{}{"min": 100.5})).As("cards").Return("cards"),
+			expected: `find().nodes({@card && balance > 100.5}) as cards return cards`,
+		},
+		{
+			name: "path template",
+			builder: uqlbuilder.New().
+				N(uqlbuilder.ID("C001")).Alias("src").Re(uqlbuilder.Schema("transfer")).Steps(1, 3).N(nil).Alias("dst").
+				As("p").Return("p{*}").Limit(5),
+			expected: `n({_id == "C001"} as src).re({@transfer})[1:3].n(as dst) as p return p{*} limit 5`,
+		},
+		{
+			name:     "path template with shortest and left edge",
+			builder:  uqlbuilder.New().N(uqlbuilder.UUID(1)).Le(nil).Shortest(5).N(uqlbuilder.UUID(2)).E(nil).Steps(2, 2).N(nil).As("p").Return("p"),
+			expected: `n({_uuid == 1}).le()[*:5].n({_uuid == 2}).e()[2].n() as p return p`,
+		},
+		{
+			name: "khop",
+			builder: uqlbuilder.New().Khop().Src(uqlbuilder.ID("A")).DepthRange(1, 2).
+				NodeFilter(uqlbuilder.Schema("account")).EdgeFilter(uqlbuilder.Gt("amount", 10)).
+				Direction(uqlbuilder.DirectionRight).Call("limit", 100).As("k").Return("k{*}"),
+			expected: `khop().src({_id == "A"}).depth(1:2).node_filter({@account}).edge_filter({amount > 10}).direction(right).limit(100) as k return k{*}`,
+		},
+		{
+			name:     "ab",
+			builder:  uqlbuilder.New().Ab().Src(uqlbuilder.ID("A")).Dest(uqlbuilder.ID("B")).Depth(3).ShortestPath().As("paths").Return("paths{*}"),
+			expected: `ab().src({_id == "A"}).dest({_id == "B"}).depth(3).shortest() as paths return paths{*}`,
+		},
+		{
+			name:     "spread",
+			builder:  uqlbuilder.New().Spread().Src(uqlbuilder.UUID(12)).Depth(2).Select("name", "my age").As("s").Return("s"),
+			expected: "spread().src({_uuid == 12}).depth(2).select(name, `my age`) as s return s",
+		},
+		{
+			name:     "insert nodes",
+			builder:  uqlbuilder.New().InsertNodes("user", map[string]interface{}{"_id": "U1", "name": "Tom", "location": types.NewPoint(1.5, 2)}).As("n").Return("n._uuid"),
+			expected: `insert().into(@user).nodes({_id: "U1", location: point({latitude: 1.5, longitude: 2}), name: "Tom"}) as n return n._uuid`,
+		},
+		{
+			name: "upsert edges",
+			builder: uqlbuilder.New().UpsertEdges("follow",
+				map[string]interface{}{"_from": "U1", "_to": "U2"},
+				map[string]interface{}{"_from": "U2", "_to": "U3", "tags": []string{"a"}},
+			),
+			expected: `upsert().into(@follow).edges([{_from: "U1", _to: "U2"}, {_from: "U2", _to: "U3", tags: ["a"]}])`,
+		},
+		{
+			name:     "update nodes",
+			builder:  uqlbuilder.New().UpdateNodes(uqlbuilder.ID("U1"), map[string]interface{}{"name": "Jerry"}).As("n").Return("n{*}"),
+			expected: `update().nodes({_id == "U1"}).set({name: "Jerry"}) as n return n{*}`,
+		},
+		{
+			name:     "delete edges",
+			builder:  uqlbuilder.New().DeleteEdges(uqlbuilder.And(uqlbuilder.Schema("follow"), uqlbuilder.Lt("since", 2000))),
+			expected: `delete().edges({@follow && since < 2000})`,
+		},
+		{
+			name: "with and group by",
+			builder: uqlbuilder.New().FindNodes(uqlbuilder.Schema("account")).As("a").
+				N(nil).Alias("a2").E(nil).N(uqlbuilder.Schema("card")).Alias("c").As("p").
+				With("a", "c").GroupBy("a.level").Return("a.level", "count(c)"),
+			expected: `find().nodes({@account}) as a n(as a2).e().n({@card} as c) as p with a, c group by a.level return a.level, count(c)`,
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := c.builder.Build()
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+			continue
+		}
+		if actual != c.expected {
+			t.Errorf("%s\nexpected: %s\nactual:   %s", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestUqlBuilderError(t *testing.T) {
+	cases := map[string]*uqlbuilder.Builder{
+		"edge without node":        uqlbuilder.New().E(nil),
+		"node after node":          uqlbuilder.New().N(nil).N(nil),
+		"steps after node":         uqlbuilder.New().N(nil).Steps(1, 2),
+		"invalid steps":            uqlbuilder.New().N(nil).E(nil).Steps(3, 1),
+		"clause without stmt":      uqlbuilder.New().Return("n"),
+		"alias without template":   uqlbuilder.New().FindNodes(nil).Alias("n"),
+		"insert without rows":      uqlbuilder.New().InsertNodes("user"),
+		"invalid value":            uqlbuilder.New().FindNodes(uqlbuilder.Eq("a", struct{}{})),
+		"invalid schema":           uqlbuilder.New().FindNodes(uqlbuilder.Schema("a`b")),
+		"call without statement":   uqlbuilder.New().Depth(1),
+		"error kept while chained": uqlbuilder.New().Return("n").FindNodes(nil).As("n"),
+	}
+
+	for name, builder := range cases {
+		if _, err := builder.Build(); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
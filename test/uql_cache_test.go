@@ -0,0 +1,87 @@
+package test
+
+import (
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"testing"
+	"time"
+)
+
+func TestNormalizeUql(t *testing.T) {
+	cases := []struct {
+		a, b string
+		same bool
+	}{
+		{"find().nodes() as n return n", "find( ).nodes()  as n\n return n // all", true},
+		{`find().nodes({name == "a b"}) as n return n`, `find().nodes({name=="a b"}) /* x */ as n return n`, true},
+		{`find().nodes({name == "a"}) as n return n`, `find().nodes({name == "A"}) as n return n`, false},
+		{"find().nodes() as n return n", "find().nodes() as N return N", false},
+	}
+	for _, c := range cases {
+		if same := utils.NormalizeUql(c.a) == utils.NormalizeUql(c.b); same != c.same {
+			t.Errorf("expected same = %t for %q and %q, normalized: %q %q", c.same, c.a, c.b, utils.NormalizeUql(c.a), utils.NormalizeUql(c.b))
+		}
+	}
+}
+
+func TestUqlCache(t *testing.T) {
+	cache := api.NewUqlCache(2, 0)
+	r1, r2, r3 := &http.UQLResponse{}, &http.UQLResponse{}, &http.UQLResponse{}
+
+	cache.Set("g1", "find().nodes() as n return n", r1)
+	cache.Set("g1", "find().edges() as e return e", r2)
+	if resp, ok := cache.Get("g1", "find().nodes()   as n return n"); !ok || resp != r1 {
+		t.Error("expected normalized uql to hit the cache")
+	}
+	if _, ok := cache.Get("g2", "find().nodes() as n return n"); ok {
+		t.Error("expected another graph to miss the cache")
+	}
+
+	// r2 is the least recently used one
+	cache.Set("g2", "find().nodes() as n return n", r3)
+	if _, ok := cache.Get("g1", "find().edges() as e return e"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", cache.Len())
+	}
+
+	cache.InvalidateGraph("g1")
+	if _, ok := cache.Get("g1", "find().nodes() as n return n"); ok {
+		t.Error("expected entries of g1 to be invalidated")
+	}
+	if _, ok := cache.Get("g2", "find().nodes() as n return n"); !ok {
+		t.Error("expected entries of g2 to be kept")
+	}
+
+	cache = api.NewUqlCache(10, 20*time.Millisecond)
+	cache.Set("g1", "find().nodes() as n return n", r1)
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := cache.Get("g1", "find().nodes() as n return n"); ok || cache.Len() != 0 {
+		t.Error("expected the entry to expire")
+	}
+}
+
+func TestUqlCacheInvalidateTargetGraph(t *testing.T) {
+	fake := &fakeUqlServer{}
+	client := newFakeServerClient(t, fake)
+	client.QueryCache = api.NewUqlCache(10, time.Minute)
+
+	uql := "find().nodes() as n return n"
+	resp := &http.UQLResponse{Status: &http.Status{Code: 0}}
+	for _, write := range []string{`truncate().graph("other")`, `compact().graph("other")`} {
+		client.QueryCache.Set(client.Config.CurrentGraph, uql, resp)
+		client.QueryCache.Set("other", uql, resp)
+
+		if _, err := client.UQL(write, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := client.QueryCache.Get("other", uql); ok {
+			t.Errorf("expected %s to invalidate the cache of graph other", write)
+		}
+		if _, ok := client.QueryCache.Get(client.Config.CurrentGraph, uql); !ok {
+			t.Errorf("expected %s to keep the cache of the current graph", write)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package test
+
+import (
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/types"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBindUqlParams(t *testing.T) {
+	cases := []struct {
+		uql      string
+		params   map[string]interface{}
+		expected string
+	}{
+		{
+			uql:      `find().nodes({name == $name}) as n return n`,
+			params:   map[string]interface{}{"name": `Tom "the cat"`},
+			expected: `find().nodes({name == "Tom \"the cat\""}) as n return n`,
+		},
+		{
+			uql:      `create().graph($name, $desc)`,
+			params:   map[string]interface{}{"name": "g1", "desc": "line1\nline2 \\ end"},
+			expected: `create().graph("g1", "line1\nline2 \\ end")`,
+		},
+		{
+			uql:      `find().nodes({age > $age && score < $score && active == $active}) as n return n`,
+			params:   map[string]interface{}{"age": int32(18), "score": 99.5, "active": true},
+			expected: `find().nodes({age > 18 && score < 99.5 && active == true}) as n return n`,
+		},
+		{
+			uql:      `find().nodes({_uuid in $uuids}) as n return n`,
+			params:   map[string]interface{}{"uuids": []uint64{1, 2, 3}},
+			expected: `find().nodes({_uuid in [1, 2, 3]}) as n return n`,
+		},
+		{
+			uql:      `find().nodes({_id in $ids}) as n return n`,
+			params:   map[string]interface{}{"ids": []string{"a", `b"`}},
+			expected: `find().nodes({_id in ["a", "b\""]}) as n return n`,
+		},
+		{
+			uql:      `find().nodes({location == $point}) as n return n`,
+			params:   map[string]interface{}{"point": types.NewPoint(39.9, 116.3)},
+			expected: `find().nodes({location == point({latitude: 39.9, longitude: 116.3})}) as n return n`,
+		},
+		{
+			uql:      `find().nodes({birthday > $time}) as n return n`,
+			params:   map[string]interface{}{"time": time.Date(2022, 1, 2, 3, 4, 5, 6000, time.UTC)},
+			expected: `find().nodes({birthday > "2022-01-02 03:04:05.000006"}) as n return n`,
+		},
+		{
+			uql:      `show().node_schema(@$schema)`,
+			params:   map[string]interface{}{"schema": utils.UqlName("my schema")},
+			expected: "show().node_schema(@`my schema`)",
+		},
+		{
+			uql:      `create().node_schema($a, $b)`,
+			params:   map[string]interface{}{"a": utils.UqlQuotedName("account"), "b": utils.UqlQuotedName("账户")},
+			expected: "create().node_schema(\"account\", `账户`)",
+		},
+		{
+			uql:      `update().nodes({_id == $id}).set($values)`,
+			params:   map[string]interface{}{"id": "u1", "values": map[string]interface{}{"name": "Bob", "age": 3, "tags": nil}},
+			expected: `update().nodes({_id == "u1"}).set({age: 3, name: "Bob", tags: null})`,
+		},
+		{
+			uql:      `find().nodes({name == "$name" && note == '$note'}) as n return n.$prop`,
+			params:   map[string]interface{}{"prop": utils.UqlName("name")},
+			expected: `find().nodes({name == "$name" && note == '$note'}) as n return n.name`,
+		},
+		{
+			uql:      `find().nodes({price == $ $p}) as n return n`,
+			params:   map[string]interface{}{"p": utils.UqlRaw("1 + 1")},
+			expected: `find().nodes({price == $ 1 + 1}) as n return n`,
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := utils.BindUqlParams(c.uql, c.params)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.uql, err)
+			continue
+		}
+		if actual != c.expected {
+			t.Errorf("%s\nexpected: %s\nactual:   %s", c.uql, c.expected, actual)
+		}
+	}
+}
+
+func TestBindUqlParamsError(t *testing.T) {
+	cases := []struct {
+		uql    string
+		params map[string]interface{}
+	}{
+		{uql: `find().nodes({name == $name})`, params: nil},
+		{uql: `find().nodes({name == $name})`, params: map[string]interface{}{"name": struct{}{}}},
+		{uql: `find().nodes({score == $score})`, params: map[string]interface{}{"score": math.NaN()}},
+		{uql: `show().node_schema(@$schema)`, params: map[string]interface{}{"schema": utils.UqlName("a`b")}},
+		{uql: `find().nodes({name == "abc})`, params: nil},
+		{uql: `find().nodes($filter)`, params: map[string]interface{}{"filter": map[int]string{1: "a"}}},
+	}
+
+	for _, c := range cases {
+		_, err := utils.BindUqlParams(c.uql, c.params)
+		if err == nil {
+			t.Errorf("%s: expected error, got nil", c.uql)
+		}
+	}
+}
+
+func TestAlterPropertyEscapesNames(t *testing.T) {
+	fake := &fakeUqlServer{}
+	client := newFakeServerClient(t, fake)
+
+	cases := []struct {
+		run    func() error
+		expect string
+	}{
+		{func() error {
+			_, err := client.AlterNodeProperty("@user.name", &structs.Property{Name: "nick", Desc: "nick name"}, nil)
+			return err
+		}, `alter().node_property(@user.name).set({name: "nick", description: "nick name"})`},
+		{func() error {
+			_, err := client.AlterEdgeProperty("@`my-schema`.`my.property`", &structs.Property{Name: "amount"}, nil)
+			return err
+		}, "alter().edge_property(@`my-schema`.`my.property`).set({name: \"amount\", description: \"\"})"},
+		{func() error {
+			_, err := client.DropNodeProperty("@*.age", nil)
+			return err
+		}, `drop().node_property(@*.age)`},
+		{func() error {
+			_, err := client.DropEdgeProperty("@transfer.my amount", nil)
+			return err
+		}, "drop().edge_property(@transfer.`my amount`)"},
+	}
+	for _, c := range cases {
+		if err := c.run(); err != nil {
+			t.Fatal(err)
+		}
+		if fake.lastUql() != c.expect {
+			t.Errorf("expected %s, got %s", c.expect, fake.lastUql())
+		}
+	}
+
+	for _, invalid := range []string{"user.name", "@user", "@user.", "@`user.name", "@user.na`me"} {
+		if _, err := client.DropNodeProperty(invalid, nil); err == nil {
+			t.Errorf("expected an error of invalid property %s", invalid)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"context"
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// fakeUserSettingServer keeps user settings in memory by user and key
+type fakeUserSettingServer struct {
+	fakeControlsServer
+	lock     sync.Mutex
+	settings map[string]string
+}
+
+func (s *fakeUserSettingServer) UserSetting(ctx context.Context, in *ultipa.UserSettingRequest) (*ultipa.UserSettingReply, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := in.UserName + "/" + in.Type
+	if in.Opt == ultipa.UserSettingRequest_OPT_SET {
+		s.settings[key] = in.Data
+		return &ultipa.UserSettingReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}}, nil
+	}
+	return &ultipa.UserSettingReply{Status: &ultipa.Status{ErrorCode: ultipa.ErrorCode_SUCCESS}, Data: s.settings[key]}, nil
+}
+
+func TestUserSetting(t *testing.T) {
+	fake := &fakeUserSettingServer{settings: map[string]string{}}
+	client := newFakeServerClient(t, fake)
+
+	if err := client.SetUserSetting("", "default_graph", "amz", nil); err != nil {
+		t.Fatal(err)
+	}
+	if fake.settings["root/default_graph"] != "amz" {
+		t.Errorf("expected setting of the current user, got %v", fake.settings)
+	}
+	data, err := client.GetUserSetting("root", "default_graph", nil)
+	if err != nil || data != "amz" {
+		t.Errorf("expected amz, got %s, %v", data, err)
+	}
+
+	type savedQuery struct {
+		Name string `json:"name"`
+		Uql  string `json:"uql"`
+	}
+	queries := []savedQuery{{Name: "accounts", Uql: "find().nodes({@account}) as n return n"}}
+	if err = client.SaveUserSettingJSON("bob", "saved_queries", queries, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded []savedQuery
+	found, err := client.LoadUserSettingJSON("bob", "saved_queries", &loaded, nil)
+	if err != nil || !found || !reflect.DeepEqual(loaded, queries) {
+		t.Errorf("expected %v, got %v, %v, %v", queries, loaded, found, err)
+	}
+
+	found, err = client.LoadUserSettingJSON("root", "saved_queries", &loaded, nil)
+	if err != nil || found {
+		t.Errorf("expected setting of root not found, got %v, %v", found, err)
+	}
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	ultipa "github.com/ultipa/ultipa-go-sdk/rpc"
+	"github.com/ultipa/ultipa-go-sdk/sdk/api"
+	"github.com/ultipa/ultipa-go-sdk/sdk/http"
+	"github.com/ultipa/ultipa-go-sdk/sdk/structs"
+	"github.com/ultipa/ultipa-go-sdk/sdk/utils"
+	"reflect"
+	"testing"
+)
+
+func TestAsUsers(t *testing.T) {
+	table := &ultipa.Table{TableName: http.RESP_USER_KEY}
+	for _, header := range []string{"username", "create", "graphPrivileges", "systemPrivileges", "policies"} {
+		table.Headers = append(table.Headers, &ultipa.Header{PropertyName: header, PropertyType: ultipa.PropertyType_STRING})
+	}
+	for _, row := range [][]string{
+		{"root", "1600000000", `{"default":["FIND","INSERT"],"amz":["FIND"]}`, `["SHOW_GRAPH","CREATE_USER"]`, `["manager"]`},
+		{"guest", "", "", "", ""},
+	} {
+		values := [][]byte{}
+		for _, v := range row {
+			values = append(values, mustBytes(t, v))
+		}
+		table.TableRows = append(table.TableRows, &ultipa.TableRow{Values: values})
+	}
+	item := &http.DataItem{Alias: http.RESP_USER_KEY, Type: ultipa.ResultType_RESULT_TYPE_TABLE, Data: table}
+
+	users, err := item.AsUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+	root := users[0]
+	if root.Username != "root" || root.CreatedTime.Unix() != 1600000000 {
+		t.Errorf("unexpected user %+v", root)
+	}
+	if !reflect.DeepEqual(root.GraphPrivileges.Graphs(), []string{"amz", "default"}) || !reflect.DeepEqual(root.GraphPrivileges["default"], []string{"FIND", "INSERT"}) {
+		t.Errorf("unexpected graph privileges %v", root.GraphPrivileges)
+	}
+	if !reflect.DeepEqual(root.SystemPrivileges, []string{"SHOW_GRAPH", "CREATE_USER"}) || !reflect.DeepEqual(root.Policies, []string{"manager"}) {
+		t.Errorf("unexpected system privileges %v or policies %v", root.SystemPrivileges, root.Policies)
+	}
+	guest := users[1]
+	if !guest.CreatedTime.IsZero() || len(guest.GraphPrivileges) != 0 || len(guest.SystemPrivileges) != 0 || len(guest.Policies) != 0 {
+		t.Errorf("unexpected user %+v", guest)
+	}
+
+	if _, err = structs.NewUser("bad", "", `["FIND"]`, "", ""); err == nil {
+		t.Error("expected invalid graph privileges to fail")
+	}
+}
+
+func TestFormatGraphPrivileges(t *testing.T) {
+	formatted, err := api.FormatGraphPrivileges(structs.GraphPrivileges{"default": {"FIND", "INSERT"}, "my graph": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uql, err := utils.BindUqlParams("grant().user($name).params({graph_privileges: $privileges})", map[string]interface{}{
+		"name":       "bob",
+		"privileges": formatted,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `grant().user("bob").params({graph_privileges: {"default": ["FIND", "INSERT"], "my graph": []}})`
+	if uql != expected {
+		t.Errorf("expected %s, got %s", expected, uql)
+	}
+}